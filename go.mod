@@ -4,4 +4,17 @@ go 1.23.1
 
 require github.com/japablazatww/libreria-a v0.0.0-20251210203640-ec455bed3c61
 
-require github.com/japablazatww/libreria-b v0.0.0-20251214232048-0ac00d21cca9
+require (
+	github.com/bufbuild/protocompile v0.14.1
+	github.com/japablazatww/libreria-b v0.0.0-20251214232048-0ac00d21cca9
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f
+	google.golang.org/grpc v1.71.1
+	google.golang.org/protobuf v1.36.4
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
@@ -1,7 +1,6 @@
 package analyzer
 
 import (
-	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -10,79 +9,256 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 
-	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/model"
 	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
 )
 
 func CrawlLibrary(currentPath string, currentNamespace string, currentImportPath string, catalog *model.Catalog, allMetadata *[]model.FunctionMetadata, debug bool) {
-	if debug {
-		fmt.Printf("DEBUG: Crawling %s (NS: %s) [Import: %s]\n", currentPath, currentNamespace, currentImportPath)
+	layout := PlanLibrary(currentPath, currentNamespace, currentImportPath, debug)
+	for _, d := range layout.Domains {
+		ParseDomain(d, debug).AddTo(catalog, allMetadata)
 	}
+	catalog.Domains = append(catalog.Domains, layout.Infos...)
+}
 
-	// 1. Read lib_config.json
-	configFile := filepath.Join(currentPath, "lib_config.json")
-	configData, err := os.ReadFile(configFile)
-	if err != nil {
+// Domain is a folder whose exported functions become services.
+type Domain struct {
+	Dir            string
+	Namespace      string // libreria-a.transfers.national
+	ImportPath     string
+	RouteNamespace string // Replaces Namespace in routes when a routePrefix applies
+}
+
+// Layout is the result of following the lib_config.json files of a
+// library, before any Go code is parsed.
+type Layout struct {
+	Domains []Domain
+	Infos   []model.DomainInfo
+}
+
+// PlanLibrary follows lib_config.json from root and lists the domains to
+// parse, in crawl order.
+func PlanLibrary(root string, namespace string, importPath string, debug bool) Layout {
+	var layout Layout
+	Walk(root, namespace, importPath, func(f Folder) {
 		if debug {
-			fmt.Printf("DEBUG: No lib_config.json in %s\n", currentPath)
+			fmt.Fprintf(os.Stderr, "DEBUG: Crawling %s (NS: %s) [Import: %s]\n", f.Dir, f.Namespace, f.ImportPath)
+		}
+		if os.IsNotExist(f.Err) {
+			if debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: No lib_config.json in %s\n", f.Dir)
+			}
+			return
+		}
+		for _, w := range f.Warnings {
+			log.Printf("Warning: %s: %s", f.ConfigFile, w)
+		}
+		if f.Err != nil {
+			log.Printf("Warning: invalid %s, skipping %s: %v", f.ConfigFile, f.Namespace, f.Err)
+			return
 		}
-		return
-	}
 
-	var config model.LibConfig
-	if err := json.Unmarshal(configData, &config); err != nil {
-		if debug {
-			fmt.Printf("DEBUG: Invalid lib_config.json in %s: %v\n", currentPath, err)
+		// If it is a domain with functions, it will be parsed
+		if f.Config.IsDomain && f.Visibility == model.VisibilityPrivate {
+			if debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Skipping private domain %s\n", f.Namespace)
+			}
+		} else if f.Config.IsDomain {
+			if debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Found Domain at %s.\n", f.Namespace)
+			}
+			layout.Domains = append(layout.Domains, Domain{
+				Dir:            f.Dir,
+				Namespace:      f.Namespace,
+				ImportPath:     f.ImportPath,
+				RouteNamespace: f.RouteNamespace,
+			})
+		}
+
+		if f.Visibility != model.VisibilityPrivate && (f.Settings.DisplayName != "" || f.Settings.RoutePrefix != "") {
+			info := model.DomainInfo{Namespace: f.Namespace, DisplayName: f.Settings.DisplayName}
+			if f.Settings.RoutePrefix != "" {
+				info.RoutePrefix = f.RouteNamespace
+			}
+			layout.Infos = append(layout.Infos, info)
+		}
+	})
+	return layout
+}
+
+// Folder is a folder reached by Walk, with the settings it inherits
+// resolved as the build applies them.
+type Folder struct {
+	Dir            string
+	Namespace      string
+	ImportPath     string
+	RouteNamespace string               // Replaces Namespace in routes once a routePrefix applies
+	Visibility     string               // Inherited from the parent unless the folder sets its own
+	Settings       model.DomainSettings // The parent's entry for the folder, overridden by its own file
+
+	ConfigFile string
+	Config     model.LibConfig
+	Warnings   []ConfigWarning
+	Err        error // Reading or decoding ConfigFile; os.IsNotExist when there is none
+
+	ListedIn string // ConfigFile of the parent, "" for the root
+}
+
+// Walk follows lib_config.json from root and calls visit for every folder
+// it reaches, parents first: the root, then the "domains" entries of each
+// folder whose file has hasNestedDomains. Entries that are not plain
+// subfolder names are not followed.
+func Walk(root string, namespace string, importPath string, visit func(Folder)) {
+	walk(Folder{Dir: root, Namespace: namespace, ImportPath: importPath, RouteNamespace: namespace, Visibility: model.VisibilityPublic}, visit)
+}
+
+// walk visits one folder. f arrives with what the parent decides: the
+// route namespace, the inherited visibility and, in Settings, the parent's
+// entry for this folder.
+func walk(f Folder, visit func(Folder)) {
+	f.ConfigFile = filepath.Join(f.Dir, "lib_config.json")
+	f.Config, f.Warnings, f.Err = LoadConfig(f.ConfigFile)
+	if f.Err == nil {
+		// Settings in the folder's own file win over the parent's entry.
+		if f.Config.DisplayName != "" {
+			f.Settings.DisplayName = f.Config.DisplayName
+		}
+		if f.Config.RoutePrefix != "" {
+			f.Settings.RoutePrefix = f.Config.RoutePrefix
+		}
+		if f.Config.Visibility != "" {
+			f.Settings.Visibility = f.Config.Visibility
 		}
+		if f.Settings.Visibility != "" {
+			f.Visibility = f.Settings.Visibility
+		}
+		if f.Settings.RoutePrefix != "" {
+			f.RouteNamespace = strings.Trim(f.Settings.RoutePrefix, "/.")
+		}
+	}
+	visit(f)
+	if f.Err != nil || !f.Config.HasNestedDomains {
 		return
 	}
 
-	// 2. If it is a domain with functions, parse them
-	if config.IsDomain {
-		if debug {
-			fmt.Printf("DEBUG: Found Domain at %s. Parsing functions...\n", currentNamespace)
+	for _, domain := range f.Config.Domains {
+		if domain == "" || domain == "." || domain == ".." || strings.ContainsAny(domain, `/\`) {
+			continue
 		}
-		meta, entries, structs := ParseLibrary(currentPath, currentNamespace, currentImportPath, debug)
-		catalog.Services = append(catalog.Services, entries...)
-		catalog.Structs = append(catalog.Structs, structs...)
-		*allMetadata = append(*allMetadata, meta...)
+		walk(Folder{
+			Dir:            filepath.Join(f.Dir, domain),
+			Namespace:      f.Namespace + "." + domain, // libreria-a.transfers.national
+			ImportPath:     f.ImportPath + "/" + domain,
+			RouteNamespace: f.RouteNamespace + "." + domain,
+			Visibility:     f.Visibility,
+			Settings:       f.Config.Nested[domain],
+			ListedIn:       f.ConfigFile,
+		}, visit)
 	}
+}
+
+// DomainResult is the analysis of one domain.
+type DomainResult struct {
+	Metadata []model.FunctionMetadata `json:"metadata"`
+	Services []model.ServiceEntry     `json:"services"`
+	Structs  []model.StructMetadata   `json:"structs"`
+	Enums    []model.EnumMetadata     `json:"enums"`
+	Types    []model.NamedType        `json:"types"`
+
+	HealthCheck *model.HealthCheck `json:"health_check,omitempty"` // The domain declares func HealthCheck() error
+}
 
-	// 3. If it has nested domains, recurse
-	if config.HasNestedDomains {
-		for _, domain := range config.Domains {
-			subPath := filepath.Join(currentPath, domain)
-			// Construct nested namespace: libreria-a.transfers.national
-			subNamespace := fmt.Sprintf("%s.%s", currentNamespace, domain)
-			// Construct import path
-			subImportPath := fmt.Sprintf("%s/%s", currentImportPath, domain)
-			CrawlLibrary(subPath, subNamespace, subImportPath, catalog, allMetadata, debug)
+// ParseDomain runs ParseLibrary on a domain and applies its route.
+func ParseDomain(d Domain, debug bool) DomainResult {
+	if debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: Parsing functions of %s...\n", d.Namespace)
+	}
+	meta, entries, structs, enums, types := ParseLibrary(d.Dir, d.Namespace, d.ImportPath, debug)
+	if d.RouteNamespace != "" && d.RouteNamespace != d.Namespace {
+		for i := range entries {
+			entries[i].Route = d.RouteNamespace + "." + entries[i].Method
+		}
+	}
+	res := DomainResult{Metadata: meta, Structs: structs, Enums: enums, Types: types}
+	for _, e := range entries {
+		if isHealthCheck(e) {
+			res.HealthCheck = &model.HealthCheck{Namespace: d.Namespace, ImportPath: d.ImportPath}
+			continue
+		}
+		res.Services = append(res.Services, e)
+	}
+	if res.HealthCheck != nil {
+		res.Metadata = nil
+		for _, m := range meta {
+			if m.Name != healthCheckFunc {
+				res.Metadata = append(res.Metadata, m)
+			}
 		}
 	}
+	return res
 }
 
-func ParseLibrary(path string, namespace string, importPath string, debug bool) ([]model.FunctionMetadata, []model.ServiceEntry, []model.StructMetadata) {
+// healthCheckFunc is the readiness hook of a domain: func HealthCheck() error.
+const healthCheckFunc = "HealthCheck"
+
+func isHealthCheck(e model.ServiceEntry) bool {
+	return e.Method == healthCheckFunc && len(e.Inputs) == 0 && len(e.Outputs) == 1 && e.Outputs[0].Type == "error"
+}
+
+// AddTo appends the result to a catalog being built.
+func (r DomainResult) AddTo(catalog *model.Catalog, allMetadata *[]model.FunctionMetadata) {
+	catalog.Services = append(catalog.Services, r.Services...)
+	catalog.Structs = append(catalog.Structs, r.Structs...)
+	catalog.Enums = append(catalog.Enums, r.Enums...)
+	catalog.Types = append(catalog.Types, r.Types...)
+	if r.HealthCheck != nil {
+		catalog.HealthChecks = append(catalog.HealthChecks, *r.HealthCheck)
+	}
+	*allMetadata = append(*allMetadata, r.Metadata...)
+}
+
+func ParseLibrary(path string, namespace string, importPath string, debug bool) ([]model.FunctionMetadata, []model.ServiceEntry, []model.StructMetadata, []model.EnumMetadata, []model.NamedType) {
 	fset := token.NewFileSet()
 	// Parse only .go files in this directory
-	pkgs, err := parser.ParseDir(fset, path, nil, parser.ParseComments)
+	pkgs, err := parser.ParseDir(fset, path, isLibraryFile, parser.ParseComments)
 	if err != nil {
 		log.Printf("Warning: error parsing %s: %v", path, err)
-		return nil, nil, nil
+		return nil, nil, nil, nil, nil
 	}
 
 	var metadata []model.FunctionMetadata
 	var entries []model.ServiceEntry
 	var structs []model.StructMetadata
+	noFlatten := make(map[string]bool) // Functions with //nexus:flatten off
+
+	// Enum candidates: named basic types (type Status string) and the typed
+	// constants declared for them. Only types with at least one constant
+	// are reported as enums; the others are reported as named types, so
+	// the generators know their underlying type.
+	namedBasics := make(map[string]string) // type name -> underlying type
+	var enumNames []string
+	enumValues := make(map[string][]model.EnumValue)
 
 	for _, pkg := range pkgs {
 		for _, file := range pkg.Files {
 			for _, decl := range file.Decls {
+				// 0. Typed constants (enum values)
+				if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.CONST {
+					collectConstValues(genDecl, enumValues)
+				}
+
 				// 1. Structs
 				if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
 					for _, spec := range genDecl.Specs {
 						if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+							if ident, ok := typeSpec.Type.(*ast.Ident); ok && typeSpec.Name.IsExported() && isBasicType(ident.Name) {
+								namedBasics[typeSpec.Name.Name] = ident.Name
+								enumNames = append(enumNames, typeSpec.Name.Name)
+							}
 							if structType, ok := typeSpec.Type.(*ast.StructType); ok {
 								if !typeSpec.Name.IsExported() {
 									continue
@@ -94,15 +270,16 @@ func ParseLibrary(path string, namespace string, importPath string, debug bool)
 									// Parse Tag
 									tag := ""
 									if field.Tag != nil {
-										tag = strings.ReplaceAll(field.Tag.Value, "`", "")
-										tag = strings.ReplaceAll(tag, "json:\"", "")
-										tag = strings.ReplaceAll(tag, "\"", "")
+										if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+											tag = unquoted
+										}
 									}
 									for _, name := range field.Names {
 										fields = append(fields, model.StructField{
 											Name:    name.Name,
 											Type:    fType,
-											JSONTag: tag,
+											JSONTag: reflect.StructTag(tag).Get("json"),
+											Tag:     tag,
 										})
 									}
 								}
@@ -119,7 +296,8 @@ func ParseLibrary(path string, namespace string, importPath string, debug bool)
 
 				// 2. Functions
 				if fn, ok := decl.(*ast.FuncDecl); ok {
-					if !fn.Name.IsExported() {
+					// Methods cannot be called by the generated wrappers
+					if !fn.Name.IsExported() || fn.Recv != nil {
 						continue
 					}
 					// Check convention: Files containing functions usually named 'functions.go'
@@ -147,6 +325,11 @@ func ParseLibrary(path string, namespace string, importPath string, debug bool)
 						}
 					}
 
+					applyValidateDirectives(fn, inputs)
+					if hasDirective(fn, flattenOffDirective) {
+						noFlatten[fname] = true
+					}
+
 					// Outputs
 					returns := []string{}
 					outputs := []model.ParamMetadata{}
@@ -188,7 +371,183 @@ func ParseLibrary(path string, namespace string, importPath string, debug bool)
 			}
 		}
 	}
-	return metadata, entries, structs
+
+	// A function taking a single struct of this package accepts its fields
+	// at the top level of params.
+	// A field bound to the same key as the param itself would make
+	// {"req": ...} ambiguous, so such functions stay nested.
+	declared := make(map[string]model.StructMetadata)
+	for _, s := range structs {
+		declared[s.Name] = s
+	}
+	for i := range entries {
+		e := &entries[i]
+		if len(e.Inputs) != 1 || noFlatten[e.Method] {
+			continue
+		}
+		s, ok := declared[e.Inputs[0].Type]
+		if !ok {
+			continue
+		}
+		e.Flatten = true
+		for _, f := range s.Fields {
+			if name := jsonName(f); name != "" && fuzzyKey(name) == fuzzyKey(e.Inputs[0].Name) {
+				log.Printf("Warning: %s: field %s.%s (%q) matches param %q, its fields are not flattened", e.Method, s.Name, f.Name, name, e.Inputs[0].Name)
+				e.Flatten = false
+			}
+		}
+	}
+
+	var enums []model.EnumMetadata
+	var types []model.NamedType
+	for _, name := range enumNames {
+		values := enumValues[name]
+		if len(values) == 0 {
+			types = append(types, model.NamedType{Name: name, Type: namedBasics[name], Namespace: namespace})
+			continue
+		}
+		enums = append(enums, model.EnumMetadata{
+			Name:      name,
+			Type:      namedBasics[name],
+			Values:    values,
+			Namespace: namespace,
+		})
+	}
+
+	return metadata, entries, structs, enums, types
+}
+
+// jsonName returns the wire name of a struct field, empty when
+// encoding/json skips it.
+func jsonName(f model.StructField) string {
+	name, _, _ := strings.Cut(f.JSONTag, ",")
+	if name == "-" && f.JSONTag == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// fuzzyKey is the key the server's fuzzy match mode compares: user_id,
+// userId and UserID are all "userid".
+func fuzzyKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// validateDirective declares rules for a param in a function's doc
+// comment: //nexus:validate amount gt=0. Directive lines are not part of
+// the description.
+const validateDirective = "//nexus:validate "
+
+// flattenOffDirective keeps the only struct input of a function nested
+// under its param name.
+const flattenOffDirective = "//nexus:flatten off"
+
+func hasDirective(fn *ast.FuncDecl, directive string) bool {
+	if fn.Doc == nil {
+		return false
+	}
+	for _, c := range fn.Doc.List {
+		if strings.TrimSpace(c.Text) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+func applyValidateDirectives(fn *ast.FuncDecl, inputs []model.ParamMetadata) {
+	if fn.Doc == nil {
+		return
+	}
+	for _, c := range fn.Doc.List {
+		rest, ok := strings.CutPrefix(c.Text, validateDirective)
+		if !ok {
+			continue
+		}
+		param, rules, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		found := false
+		for i := range inputs {
+			if inputs[i].Name == util.ToSnakeCase(param) {
+				inputs[i].Validate = strings.TrimSpace(rules)
+				found = true
+			}
+		}
+		if !found {
+			log.Printf("Warning: %s: //nexus:validate names unknown param %q", fn.Name.Name, param)
+		}
+	}
+}
+
+// collectConstValues records every exported constant declared with an
+// explicit named type, following Go's implicit repetition rules so that
+// iota blocks are attributed to the right type.
+func collectConstValues(genDecl *ast.GenDecl, values map[string][]model.EnumValue) {
+	currentType := ""
+	var lastExpr ast.Expr
+
+	for index, spec := range genDecl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		if valueSpec.Type != nil {
+			currentType = ""
+			if ident, ok := valueSpec.Type.(*ast.Ident); ok {
+				currentType = ident.Name
+			}
+		} else if len(valueSpec.Values) > 0 {
+			// New untyped expression: no longer part of a typed block
+			currentType = ""
+		}
+		if len(valueSpec.Values) > 0 {
+			lastExpr = valueSpec.Values[0]
+		}
+
+		if currentType == "" {
+			continue
+		}
+
+		for _, name := range valueSpec.Names {
+			if !name.IsExported() {
+				continue
+			}
+			values[currentType] = append(values[currentType], model.EnumValue{
+				Name:  name.Name,
+				Value: constLiteral(lastExpr, index),
+			})
+		}
+	}
+}
+
+func constLiteral(expr ast.Expr, index int) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			if v, err := strconv.Unquote(e.Value); err == nil {
+				return v
+			}
+		}
+		return e.Value
+	case *ast.Ident:
+		if e.Name == "iota" {
+			return strconv.Itoa(index)
+		}
+	}
+	return ""
+}
+
+func isBasicType(name string) bool {
+	switch name {
+	case "string", "bool",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "byte", "rune":
+		return true
+	}
+	return false
 }
 
 func EnsureLibraryInstalled(widthDir string, pkg string, version string, debug bool) error {
@@ -201,26 +560,44 @@ func EnsureLibraryInstalled(widthDir string, pkg string, version string, debug b
 		return fmt.Errorf("error running go get: %s\nOutput: %s", err, string(output))
 	}
 	if debug {
-		fmt.Printf("\nDEBUG: go get output:\n%s\n", string(output))
+		fmt.Fprintf(os.Stderr, "\nDEBUG: go get output:\n%s\n", string(output))
 	}
 
 	return nil
 }
 
-func ResolvePackagePath(withDir string, pkg string, debug bool) (string, error) {
+// ReadModulePath returns the module path declared in dir/go.mod.
+func ReadModulePath(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok {
+			if path := strings.Trim(strings.TrimSpace(rest), `"`); path != "" {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s has no module directive", filepath.Join(dir, "go.mod"))
+}
+
+// ResolvePackagePath returns the root directory of an installed module and
+// the version it resolved to.
+func ResolvePackagePath(withDir string, pkg string, debug bool) (string, string, error) {
 	// Use -m to resolve the Module Root, as the root might not be a package anymore (no .go files)
-	cmd := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", pkg)
+	cmd := exec.Command("go", "list", "-m", "-f", "{{.Version}} {{.Dir}}", pkg)
 	cmd.Dir = withDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if debug {
-			fmt.Printf("DEBUG: go list error output:\n%s\n", string(output))
+			fmt.Fprintf(os.Stderr, "DEBUG: go list error output:\n%s\n", string(output))
 		}
-		return "", fmt.Errorf("go list failed: %v", err)
+		return "", "", fmt.Errorf("go list failed: %v", err)
 	}
-	path := strings.TrimSpace(string(output))
+	version, path, _ := strings.Cut(strings.TrimSpace(string(output)), " ")
 	if debug {
-		fmt.Printf("DEBUG: Raw path bytes: %x\n", path)
+		fmt.Fprintf(os.Stderr, "DEBUG: Raw path bytes: %x\n", path)
 	}
-	return path, nil
+	return path, version, nil
 }
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// DiscoverLibrary is the "discovery": "auto" alternative to CrawlLibrary for
+// libraries without lib_config.json files. See PlanAuto.
+func DiscoverLibrary(root string, namespace string, importPath string, include []string, exclude []string, catalog *model.Catalog, allMetadata *[]model.FunctionMetadata, debug bool) {
+	for _, d := range PlanAuto(root, namespace, importPath, include, exclude, debug).Domains {
+		ParseDomain(d, debug).AddTo(catalog, allMetadata)
+	}
+}
+
+// PlanAuto lists the domains of a library by convention: every package
+// below root that is not internal, not a test or main package and has
+// exported functions; its folder path becomes the namespace
+// (legacy/payments/cards -> legacy.payments.cards). include and exclude
+// are globs over the package path relative to root ("." is the root).
+func PlanAuto(root string, namespace string, importPath string, include []string, exclude []string, debug bool) Layout {
+	var layout Layout
+	filepath.WalkDir(root, func(dir string, d os.DirEntry, err error) error {
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, dir)
+		rel = filepath.ToSlash(rel)
+
+		if rel != "." {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "testdata" || name == "vendor" || name == "internal" {
+				return filepath.SkipDir
+			}
+			// A nested go.mod is another module
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		if !MatchPackage(rel, include, exclude) {
+			if debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: Auto discovery skips %s (include/exclude)\n", rel)
+			}
+			return nil
+		}
+		if !hasExportedFuncs(dir) {
+			return nil
+		}
+
+		subNamespace, subImportPath := namespace, importPath
+		if rel != "." {
+			subNamespace = namespace + "." + strings.ReplaceAll(rel, "/", ".")
+			subImportPath = importPath + "/" + rel
+		}
+		if debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: Auto discovery found domain %s at %s\n", subNamespace, dir)
+		}
+		layout.Domains = append(layout.Domains, Domain{Dir: dir, Namespace: subNamespace, ImportPath: subImportPath, RouteNamespace: subNamespace})
+		return nil
+	})
+	return layout
+}
+
+// MatchPackage reports whether the package at rel ("." for the root) is
+// selected: it matches an include glob (or include is empty) and no
+// exclude glob. "**" matches any number of folders.
+func MatchPackage(rel string, include []string, exclude []string) bool {
+	for _, g := range exclude {
+		if matchGlob(g, rel) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, g := range include {
+		if matchGlob(g, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlob(pattern string, rel string) bool {
+	var parts []string
+	if rel != "." && rel != "" {
+		parts = strings.Split(rel, "/")
+	}
+	var segments []string
+	if p := strings.Trim(pattern, "/"); p != "." && p != "" {
+		segments = strings.Split(p, "/")
+	}
+	return matchSegments(segments, parts)
+}
+
+func matchSegments(pattern []string, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+// hasExportedFuncs reports whether dir holds an importable package with at
+// least one exported top-level function.
+func hasExportedFuncs(dir string) bool {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, isLibraryFile, parser.SkipObjectResolution)
+	if err != nil {
+		return false
+	}
+	for name, pkg := range pkgs {
+		if name == "main" {
+			continue
+		}
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.IsExported() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isLibraryFile keeps the files Nexus parses: Go sources other than tests.
+func isLibraryFile(fi os.FileInfo) bool {
+	return strings.HasSuffix(fi.Name(), ".go") && !strings.HasSuffix(fi.Name(), "_test.go")
+}
@@ -0,0 +1,275 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// cacheFormat is part of every cache key; bump it when DomainResult or
+// the way it is computed changes.
+const cacheFormat = "5"
+
+// Cache stores the analysis of every domain on disk, keyed by the module
+// version and the hashes of the domain's Go files, so that builds only parse
+// what changed. It also remembers where pinned module versions live in the
+// module cache, so they are not fetched again. A nil *Cache disables
+// caching.
+type Cache struct {
+	dir    string
+	salt   string // Identifies the analyzer that wrote the entries
+	Hits   int
+	Misses int
+}
+
+// DefaultCacheDir returns ~/.nexus/cache.
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".nexus", "cache"), nil
+}
+
+// OpenCache uses dir as the cache, creating it if needed.
+func OpenCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	// Entries written by another build of nexus-cli are not trusted: its
+	// analyzer may produce different results.
+	salt := cacheFormat
+	if exe, err := os.Executable(); err == nil {
+		if sum, err := hashFile(exe); err == nil {
+			salt += ":" + sum
+		}
+	}
+	return &Cache{dir: dir, salt: salt}, nil
+}
+
+// moduleRecord is the cached location of module@version, with what is
+// checked before trusting it again.
+type moduleRecord struct {
+	Module      string `json:"module"`
+	Version     string `json:"version"`
+	Dir         string `json:"dir"`
+	ZipHash     string `json:"ziphash,omitempty"` // h1: sum recorded by go in the module cache
+	Fingerprint string `json:"fingerprint"`       // See dirFingerprint
+}
+
+var pinnedVersion = regexp.MustCompile(`^v\d+\.\d+\.\d+`)
+
+// ModuleDir returns the directory of module@version when it was resolved
+// by an earlier build and is unchanged on disk: its files and the sum go
+// recorded for the download are the ones seen then. Only pinned versions
+// (v1.2.3, pseudo-versions) are looked up: branches like develop can move.
+func (c *Cache) ModuleDir(module string, version string) (string, bool) {
+	if c == nil || !pinnedVersion.MatchString(version) {
+		return "", false
+	}
+	var rec moduleRecord
+	if !c.read(c.path("modules", module+"@"+version), &rec) || rec.Dir == "" {
+		return "", false
+	}
+	if _, err := os.Stat(filepath.Join(rec.Dir, "go.mod")); err != nil {
+		return "", false
+	}
+	if zipHash(rec.Dir, module, version) != rec.ZipHash {
+		return "", false
+	}
+	if fp, err := dirFingerprint(rec.Dir); err != nil || fp != rec.Fingerprint {
+		return "", false
+	}
+	return rec.Dir, true
+}
+
+// PutModuleDir records where module@version was installed.
+func (c *Cache) PutModuleDir(module string, version string, dir string) {
+	if c == nil || !pinnedVersion.MatchString(version) {
+		return
+	}
+	fp, err := dirFingerprint(dir)
+	if err != nil {
+		return
+	}
+	c.write(c.path("modules", module+"@"+version), moduleRecord{
+		Module:      module,
+		Version:     version,
+		Dir:         dir,
+		ZipHash:     zipHash(dir, module, version),
+		Fingerprint: fp,
+	})
+}
+
+// zipHash returns the sum go recorded when it downloaded module@version
+// (cache/download/<module>/@v/<version>.ziphash), empty when dir is not in
+// a module cache.
+func zipHash(dir string, module string, version string) string {
+	suffix := string(filepath.Separator) + filepath.FromSlash(escapeModulePath(module)) + "@" + version
+	root, ok := strings.CutSuffix(filepath.Clean(dir), suffix)
+	if !ok {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(root, "cache", "download", filepath.FromSlash(escapeModulePath(module)), "@v", version+".ziphash"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// escapeModulePath writes upper-case letters as in the module cache:
+// github.com/Foo/bar -> github.com/!foo/bar
+func escapeModulePath(module string) string {
+	var sb strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// dirFingerprint hashes the path, size and modification time of every file
+// under dir. It changes when files are added, removed or rewritten, without
+// reading their contents.
+func dirFingerprint(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		fmt.Fprintf(h, "%s %d %d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParseDomain returns the cached analysis of d, or parses it and stores
+// the result. version is the resolved version of the module.
+func (c *Cache) ParseDomain(d Domain, version string, debug bool) DomainResult {
+	if c == nil {
+		return ParseDomain(d, debug)
+	}
+	key, err := c.domainKey(d, version)
+	if err != nil {
+		if debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: Not caching %s: %v\n", d.Namespace, err)
+		}
+		return ParseDomain(d, debug)
+	}
+
+	file := c.path("domains", key)
+	var res DomainResult
+	if c.read(file, &res) {
+		c.Hits++
+		if debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: Cache hit for %s\n", d.Namespace)
+		}
+		return res
+	}
+	c.Misses++
+	res = ParseDomain(d, debug)
+	c.write(file, res)
+	return res
+}
+
+// domainKey hashes everything ParseDomain depends on: where the domain
+// sits in the catalog and the contents of its Go files.
+func (c *Cache) domainKey(d Domain, version string) (string, error) {
+	entries, err := os.ReadDir(d.Dir)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%s\n%s\n", c.salt, version, d.Namespace, d.ImportPath, d.RouteNamespace, d.Dir)
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".go" {
+			if info, err := e.Info(); err == nil && isLibraryFile(info) {
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sum, err := hashFile(filepath.Join(d.Dir, name))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %s\n", name, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *Cache) path(kind string, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, kind, name[:2], name+".json")
+}
+
+func (c *Cache) read(file string, v interface{}) bool {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// write stores v atomically; errors only cost a cache miss later.
+func (c *Cache) write(file string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
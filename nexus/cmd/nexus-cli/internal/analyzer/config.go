@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// ConfigVersion is the newest lib_config.json version understood. Version 1
+// (or no "version" key) is the original format; version 2 adds display
+// names, route prefixes and visibility, and "domains" entries may be objects.
+const ConfigVersion = 2
+
+// ConfigWarning is a problem in lib_config.json that does not stop the
+// crawl, e.g. an unknown key. Key is the offending key, for positions.
+type ConfigWarning struct {
+	Key     string
+	Message string
+}
+
+func (w ConfigWarning) String() string {
+	return w.Message
+}
+
+// ConfigError is an invalid setting. Key is the offending key as spelled
+// in the file.
+type ConfigError struct {
+	Key string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+// configKeys maps every accepted spelling to its canonical key. The
+// standard documents snake_case; libreria-a and libreria-b use camelCase.
+var configKeys = map[string]string{
+	"version":            "version",
+	"isDomain":           "isDomain",
+	"is_domain":          "isDomain",
+	"hasNestedDomains":   "hasNestedDomains",
+	"has_nested_domains": "hasNestedDomains",
+	"domains":            "domains",
+	"displayName":        "displayName",
+	"display_name":       "displayName",
+	"routePrefix":        "routePrefix",
+	"route_prefix":       "routePrefix",
+	"visibility":         "visibility",
+}
+
+// domainEntryKeys are the keys of an object entry in "domains" (version 2).
+var domainEntryKeys = map[string]string{
+	"name":         "name",
+	"displayName":  "displayName",
+	"display_name": "displayName",
+	"routePrefix":  "routePrefix",
+	"route_prefix": "routePrefix",
+	"visibility":   "visibility",
+}
+
+// v2Keys are only honored with "version": 2.
+var v2Keys = map[string]bool{"displayName": true, "routePrefix": true, "visibility": true}
+
+// LoadConfig reads and parses a lib_config.json file.
+func LoadConfig(path string) (model.LibConfig, []ConfigWarning, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model.LibConfig{}, nil, err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig decodes lib_config.json, accepting camelCase and snake_case
+// keys. Unknown keys, and settings the declared version does not support,
+// are ignored and reported as warnings. Invalid JSON, wrong value types
+// and unsupported versions are errors.
+func ParseConfig(data []byte) (model.LibConfig, []ConfigWarning, error) {
+	var config model.LibConfig
+	var warnings []ConfigWarning
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return config, nil, err
+	}
+
+	values, warns := canonicalKeys(raw, configKeys, "")
+	warnings = append(warnings, warns...)
+
+	if v, ok := values["version"]; ok {
+		if err := json.Unmarshal(v.value, &config.Version); err != nil {
+			return config, warnings, &ConfigError{Key: v.key, Err: fmt.Errorf("must be a number")}
+		}
+		if config.Version < 1 || config.Version > ConfigVersion {
+			return config, warnings, &ConfigError{Key: v.key, Err: fmt.Errorf("unsupported version %d (supported: 1 to %d)", config.Version, ConfigVersion)}
+		}
+	}
+	version := config.Version
+	if version == 0 {
+		version = 1
+	}
+
+	for _, canonical := range sortedKeys(values) {
+		v := values[canonical]
+		if version < 2 && v2Keys[canonical] {
+			warnings = append(warnings, ConfigWarning{Key: v.key, Message: fmt.Sprintf("key %q requires \"version\": 2 and is ignored", v.key)})
+			continue
+		}
+		var err error
+		switch canonical {
+		case "isDomain":
+			err = decodeValue(v.value, &config.IsDomain)
+		case "hasNestedDomains":
+			err = decodeValue(v.value, &config.HasNestedDomains)
+		case "displayName":
+			err = decodeValue(v.value, &config.DisplayName)
+		case "routePrefix":
+			err = decodeValue(v.value, &config.RoutePrefix)
+		case "visibility":
+			err = decodeValue(v.value, &config.Visibility)
+			if err == nil {
+				err = checkVisibility(config.Visibility)
+			}
+		case "domains":
+			var w []ConfigWarning
+			w, err = parseDomains(v.value, version, &config)
+			warnings = append(warnings, w...)
+		}
+		if err != nil {
+			return config, warnings, &ConfigError{Key: v.key, Err: err}
+		}
+	}
+	return config, warnings, nil
+}
+
+func parseDomains(data json.RawMessage, version int, config *model.LibConfig) ([]ConfigWarning, error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("must be a list")
+	}
+
+	var warnings []ConfigWarning
+	for i, entry := range entries {
+		var name string
+		if err := json.Unmarshal(entry, &name); err == nil {
+			config.Domains = append(config.Domains, name)
+			continue
+		}
+		if version < 2 {
+			return warnings, fmt.Errorf("entry %d must be a folder name (objects require \"version\": 2)", i)
+		}
+
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(entry, &raw); err != nil {
+			return warnings, fmt.Errorf("entry %d must be a folder name or an object", i)
+		}
+		values, warns := canonicalKeys(raw, domainEntryKeys, fmt.Sprintf("domains[%d].", i))
+		warnings = append(warnings, warns...)
+
+		v, ok := values["name"]
+		if !ok {
+			return warnings, fmt.Errorf("entry %d has no \"name\"", i)
+		}
+		if err := json.Unmarshal(v.value, &name); err != nil {
+			return warnings, fmt.Errorf("entry %d: name must be a string", i)
+		}
+
+		var settings model.DomainSettings
+		for canonical, v := range values {
+			var err error
+			switch canonical {
+			case "displayName":
+				err = decodeValue(v.value, &settings.DisplayName)
+			case "routePrefix":
+				err = decodeValue(v.value, &settings.RoutePrefix)
+			case "visibility":
+				err = decodeValue(v.value, &settings.Visibility)
+				if err == nil {
+					err = checkVisibility(settings.Visibility)
+				}
+			}
+			if err != nil {
+				return warnings, fmt.Errorf("entry %q: %s: %v", name, v.key, err)
+			}
+		}
+
+		config.Domains = append(config.Domains, name)
+		if config.Nested == nil {
+			config.Nested = make(map[string]model.DomainSettings)
+		}
+		config.Nested[name] = settings
+	}
+	return warnings, nil
+}
+
+// decodeValue unmarshals one setting, with a short message on type errors.
+func decodeValue(data json.RawMessage, dst interface{}) error {
+	err := json.Unmarshal(data, dst)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("must be %s, not %s", typeErr.Type, typeErr.Value)
+	}
+	return err
+}
+
+func checkVisibility(v string) error {
+	switch v {
+	case "", model.VisibilityPublic, model.VisibilityPrivate:
+		return nil
+	}
+	return fmt.Errorf("visibility must be %q or %q, not %q", model.VisibilityPublic, model.VisibilityPrivate, v)
+}
+
+type configValue struct {
+	key   string // Spelling used in the file
+	value json.RawMessage
+}
+
+// canonicalKeys maps the keys of raw to their canonical names, warning
+// about unknown keys and about a key given in both spellings.
+func canonicalKeys(raw map[string]json.RawMessage, known map[string]string, prefix string) (map[string]configValue, []ConfigWarning) {
+	values := make(map[string]configValue)
+	var warnings []ConfigWarning
+	for _, key := range sortedKeys(raw) {
+		canonical, ok := known[key]
+		if !ok {
+			warnings = append(warnings, ConfigWarning{Key: key, Message: fmt.Sprintf("unknown key %q is ignored (expected one of: %s)", prefix+key, strings.Join(canonicalNames(known), ", "))})
+			continue
+		}
+		if prev, dup := values[canonical]; dup {
+			warnings = append(warnings, ConfigWarning{Key: key, Message: fmt.Sprintf("%q and %q are the same setting; using %q", prefix+prev.key, prefix+key, prefix+canonical)})
+			if key != canonical {
+				continue
+			}
+		}
+		values[canonical] = configValue{key: key, value: raw[key]}
+	}
+	return values, warnings
+}
+
+func canonicalNames(known map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, canonical := range known {
+		if !seen[canonical] {
+			seen[canonical] = true
+			names = append(names, canonical)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+)
+
+// Discovery modes of a registry entry.
+const (
+	DiscoveryConfig = "config" // Follow lib_config.json files (default)
+	DiscoveryAuto   = "auto"   // Every non-internal package with exported functions is a domain
+)
+
+// DefaultVersion is the version installed when an entry does not set one.
+const DefaultVersion = "develop"
+
+// RegistryEntry is one library of registry.json. An entry is either the
+// module path or an object:
+//
+//	{"module": "github.com/acme/legacy", "discovery": "auto",
+//	 "include": ["payments/**"], "exclude": ["**/mocks"]}
+type RegistryEntry struct {
+	Module    string   `json:"module"`
+	Version   string   `json:"version,omitempty"`   // Passed to 'go get' (default DefaultVersion)
+	Discovery string   `json:"discovery,omitempty"` // DiscoveryConfig or DiscoveryAuto
+	Include   []string `json:"include,omitempty"`   // Auto: package globs to scan ("**" spans folders); empty means all
+	Exclude   []string `json:"exclude,omitempty"`   // Auto: package globs to skip
+}
+
+func (e *RegistryEntry) UnmarshalJSON(data []byte) error {
+	var module string
+	if err := json.Unmarshal(data, &module); err == nil {
+		*e = RegistryEntry{Module: module}
+		return nil
+	}
+	type plain RegistryEntry
+	var p plain
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return err
+	}
+	*e = RegistryEntry(p)
+	return nil
+}
+
+// ParseRegistry decodes registry.json and fills in defaults.
+func ParseRegistry(data []byte) ([]RegistryEntry, error) {
+	var entries []RegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		e := &entries[i]
+		if e.Module == "" {
+			return nil, fmt.Errorf("entry %d: module is required", i)
+		}
+		if e.Version == "" {
+			e.Version = DefaultVersion
+		}
+		switch e.Discovery {
+		case "":
+			e.Discovery = DiscoveryConfig
+		case DiscoveryConfig, DiscoveryAuto:
+		default:
+			return nil, fmt.Errorf("%s: unknown discovery mode %q (use %q or %q)", e.Module, e.Discovery, DiscoveryConfig, DiscoveryAuto)
+		}
+		if e.Discovery != DiscoveryAuto && (len(e.Include) > 0 || len(e.Exclude) > 0) {
+			return nil, fmt.Errorf("%s: include/exclude require \"discovery\": %q", e.Module, DiscoveryAuto)
+		}
+		for _, g := range append(append([]string{}, e.Include...), e.Exclude...) {
+			if _, err := path.Match(g, ""); err != nil {
+				return nil, fmt.Errorf("%s: invalid glob %q: %v", e.Module, g, err)
+			}
+		}
+	}
+	return entries, nil
+}
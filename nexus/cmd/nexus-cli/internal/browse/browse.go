@@ -0,0 +1,486 @@
+// Package browse implements `nexus-cli browse`, an interactive catalog
+// browser drawn with plain ANSI escape sequences.
+package browse
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/japablazatww/nexus/nexus/model"
+	"github.com/japablazatww/nexus/nexus/search"
+)
+
+type Options struct {
+	BaseURL string // Used by the copied SDK and curl snippets
+}
+
+// node is one namespace level: libreria-a -> transfers -> national
+type node struct {
+	name     string
+	path     string // Full namespace: libreria-a.transfers
+	title    string // displayName from lib_config.json, if any
+	parent   *node
+	children []*node
+	services []model.ServiceEntry
+}
+
+// entry is one selectable line of the list.
+type entry struct {
+	label   string
+	node    *node
+	service *model.ServiceEntry
+}
+
+type browser struct {
+	opts     Options
+	root     *node
+	current  *node
+	cursor   int
+	offset   int
+	filter   string
+	editing  bool // Typing into the filter
+	status   string
+	index    *search.Index
+	snippets *Snippets
+}
+
+// Run starts the browser on the current terminal and returns when the user
+// quits.
+func Run(catalog model.Catalog, opts Options) error {
+	term, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer term.restore()
+
+	b := &browser{
+		opts:     opts,
+		root:     buildTree(catalog),
+		index:    search.NewIndex(catalog),
+		snippets: NewSnippets(catalog),
+	}
+	b.current = b.root
+
+	buf := make([]byte, 16)
+	for {
+		width, height := term.size()
+		b.render(os.Stdout, width, height)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			if quit := b.handleKey(k); quit {
+				return nil
+			}
+		}
+	}
+}
+
+func buildTree(catalog model.Catalog) *node {
+	titles := make(map[string]string)
+	for _, d := range catalog.Domains {
+		titles[d.Namespace] = d.DisplayName
+	}
+
+	root := &node{}
+	for _, svc := range catalog.Services {
+		current := root
+		for _, part := range strings.Split(svc.Namespace, ".") {
+			var child *node
+			for _, c := range current.children {
+				if c.name == part {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				path := part
+				if current.path != "" {
+					path = current.path + "." + part
+				}
+				child = &node{name: part, path: path, title: titles[path], parent: current}
+				current.children = append(current.children, child)
+			}
+			current = child
+		}
+		current.services = append(current.services, svc)
+	}
+
+	var sortTree func(n *node)
+	sortTree = func(n *node) {
+		sort.Slice(n.children, func(i, j int) bool { return n.children[i].name < n.children[j].name })
+		sort.SliceStable(n.services, func(i, j int) bool { return n.services[i].Method < n.services[j].Method })
+		for _, c := range n.children {
+			sortTree(c)
+		}
+	}
+	sortTree(root)
+	return root
+}
+
+// entries is what the list shows: the current namespace level, or the
+// ranked matches of the whole catalog while a filter is set.
+func (b *browser) entries() []entry {
+	var list []entry
+	if b.filter != "" {
+		for _, res := range b.index.Search(b.filter, 0) {
+			if svc := b.find(res.Namespace, res.Method); svc != nil {
+				list = append(list, entry{label: res.Namespace + "." + res.Method, service: svc})
+			}
+		}
+		return list
+	}
+	for _, c := range b.current.children {
+		label := c.name + "/"
+		if c.title != "" {
+			label += "  " + c.title
+		}
+		list = append(list, entry{label: label, node: c})
+	}
+	for i := range b.current.services {
+		svc := &b.current.services[i]
+		list = append(list, entry{label: svc.Method, service: svc})
+	}
+	return list
+}
+
+func (b *browser) find(namespace, method string) *model.ServiceEntry {
+	n := b.root
+	for _, part := range strings.Split(namespace, ".") {
+		var next *node
+		for _, c := range n.children {
+			if c.name == part {
+				next = c
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	for i := range n.services {
+		if n.services[i].Method == method {
+			return &n.services[i]
+		}
+	}
+	return nil
+}
+
+// --- Input ---
+
+type key struct {
+	name string // "up", "down", "left", "right", "enter", "esc", "backspace", "ctrl-c" or "" for text
+	char rune
+}
+
+// parseKeys splits one read from the terminal into key presses; a read
+// holds several keys when typing fast or pasting into the filter.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		k, n := parseKey(b)
+		keys = append(keys, k)
+		b = b[n:]
+	}
+	return keys
+}
+
+// parseKey decodes the first key of b and returns how many bytes it used.
+func parseKey(b []byte) (key, int) {
+	switch {
+	case len(b) >= 3 && b[0] == 0x1b && (b[1] == '[' || b[1] == 'O'):
+		// CSI sequence: ESC [ params final-byte
+		n := 2
+		for n < len(b) && (b[n] < 0x40 || b[n] > 0x7e) {
+			n++
+		}
+		if n == len(b) {
+			return key{}, len(b)
+		}
+		switch b[n] {
+		case 'A':
+			return key{name: "up"}, n + 1
+		case 'B':
+			return key{name: "down"}, n + 1
+		case 'C':
+			return key{name: "right"}, n + 1
+		case 'D':
+			return key{name: "left"}, n + 1
+		}
+		return key{}, n + 1
+	case b[0] == 0x1b:
+		return key{name: "esc"}, 1
+	case b[0] == '\r' || b[0] == '\n':
+		return key{name: "enter"}, 1
+	case b[0] == 0x7f || b[0] == 0x08:
+		return key{name: "backspace"}, 1
+	case b[0] == 0x03:
+		return key{name: "ctrl-c"}, 1
+	}
+	r, size := utf8.DecodeRune(b)
+	return key{char: r}, size
+}
+
+// handleKey applies one key press and reports whether to quit.
+func (b *browser) handleKey(k key) bool {
+	b.status = ""
+	if k.name == "ctrl-c" {
+		return true
+	}
+
+	if b.editing {
+		switch {
+		case k.name == "esc":
+			b.editing, b.filter = false, ""
+		case k.name == "enter":
+			b.editing = false
+		case k.name == "backspace":
+			if _, size := utf8.DecodeLastRuneInString(b.filter); size > 0 {
+				b.filter = b.filter[:len(b.filter)-size]
+			}
+		case k.name == "up", k.name == "down":
+			b.move(k.name)
+		case k.char >= ' ':
+			b.filter += string(k.char)
+		}
+		b.cursor, b.offset = clampCursor(b.cursor, len(b.entries())), 0
+		return false
+	}
+
+	list := b.entries()
+	var selected *entry
+	if b.cursor < len(list) {
+		selected = &list[b.cursor]
+	}
+
+	switch {
+	case k.char == 'q':
+		return true
+	case k.name == "up", k.char == 'k', k.name == "down", k.char == 'j':
+		if k.char == 'k' {
+			k.name = "up"
+		} else if k.char == 'j' {
+			k.name = "down"
+		}
+		b.move(k.name)
+	case k.name == "right", k.name == "enter", k.char == 'l':
+		if selected != nil && selected.node != nil {
+			b.current, b.cursor, b.offset = selected.node, 0, 0
+		}
+	case k.name == "left", k.name == "backspace", k.char == 'h':
+		if b.filter != "" {
+			b.filter, b.cursor, b.offset = "", 0, 0
+		} else if b.current.parent != nil {
+			b.cursor = indexOf(b.current.parent.children, b.current)
+			b.current, b.offset = b.current.parent, 0
+		}
+	case k.name == "esc":
+		b.filter, b.cursor, b.offset = "", 0, 0
+	case k.char == '/':
+		b.editing = true
+	case k.char == 'c', k.char == 'u':
+		if selected == nil || selected.service == nil {
+			b.status = "Select a service first"
+			break
+		}
+		text, what := b.snippets.GoSDK(*selected.service, b.opts.BaseURL), "Go SDK call"
+		if k.char == 'u' {
+			text, what = b.snippets.Curl(*selected.service, b.opts.BaseURL), "curl command"
+		}
+		b.status = fmt.Sprintf("Copied %s via %s", what, copyToClipboard(text))
+	}
+	return false
+}
+
+func (b *browser) move(dir string) {
+	n := len(b.entries())
+	if dir == "up" && b.cursor > 0 {
+		b.cursor--
+	}
+	if dir == "down" && b.cursor < n-1 {
+		b.cursor++
+	}
+}
+
+func clampCursor(cursor, n int) int {
+	if cursor >= n {
+		cursor = n - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
+
+func indexOf(nodes []*node, n *node) int {
+	for i, c := range nodes {
+		if c == n {
+			return i
+		}
+	}
+	return 0
+}
+
+// --- Rendering ---
+
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+)
+
+func (b *browser) render(w io.Writer, width, height int) {
+	if height < 8 {
+		height = 8
+	}
+	list := b.entries()
+	b.cursor = clampCursor(b.cursor, len(list))
+
+	var lines []string
+	header := "Nexus catalog  /" + strings.ReplaceAll(b.current.path, ".", "/")
+	switch {
+	case b.editing:
+		header = "Filter: " + b.filter + "_"
+	case b.filter != "":
+		header = fmt.Sprintf("Filter: %s  (%d matches, esc to clear)", b.filter, len(list))
+	}
+	lines = append(lines, styleBold+truncate(header, width)+styleReset)
+
+	// The list takes the upper ~40% of the screen, details the rest
+	listHeight := (height - 3) * 2 / 5
+	if listHeight < 3 {
+		listHeight = 3
+	}
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+listHeight {
+		b.offset = b.cursor - listHeight + 1
+	}
+	for i := b.offset; i < b.offset+listHeight; i++ {
+		if i >= len(list) {
+			lines = append(lines, "")
+			continue
+		}
+		label := "  " + list[i].label
+		if i == b.cursor {
+			lines = append(lines, styleReverse+pad(truncate(label, width), width)+styleReset)
+		} else {
+			lines = append(lines, truncate(label, width))
+		}
+	}
+	if len(list) == 0 {
+		lines[1] = styleDim + "  (nothing here)" + styleReset
+	}
+
+	lines = append(lines, styleDim+strings.Repeat("─", width)+styleReset)
+
+	var detail []string
+	if b.cursor < len(list) {
+		detail = b.details(list[b.cursor])
+	}
+	detailHeight := height - len(lines) - 1
+	for i := 0; i < detailHeight; i++ {
+		if i < len(detail) {
+			lines = append(lines, truncate(detail[i], width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	footer := "↑↓ move  → open  ← back  / filter  c copy SDK call  u copy curl  q quit"
+	if b.status != "" {
+		footer = b.status
+	}
+	lines = append(lines, styleDim+truncate(footer, width)+styleReset)
+
+	fmt.Fprint(w, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+}
+
+// details describes the selected entry: a namespace summary, or a service
+// with its docs, parameters and the structs it uses.
+func (b *browser) details(e entry) []string {
+	if e.node != nil {
+		header := "Namespace " + e.node.path
+		if e.node.title != "" {
+			header += " (" + e.node.title + ")"
+		}
+		return []string{
+			header,
+			fmt.Sprintf("%d services, %d sub-namespaces", countServices(e.node), len(e.node.children)),
+		}
+	}
+
+	svc := *e.service
+	lines := []string{styleBold + svc.Namespace + "." + svc.Method + styleReset, "  " + search.Signature(svc)}
+	if svc.Route != "" {
+		lines = append(lines, "  POST /"+svc.Endpoint())
+	}
+	if svc.Description != "" {
+		lines = append(lines, "")
+		for _, l := range strings.Split(strings.TrimSpace(svc.Description), "\n") {
+			lines = append(lines, "  "+l)
+		}
+	}
+	lines = append(lines, "", "Inputs:")
+	if len(svc.Inputs) == 0 {
+		lines = append(lines, "  (none)")
+	}
+	for _, p := range svc.Inputs {
+		lines = append(lines, fmt.Sprintf("  %-20s %s", p.Name, p.Type))
+	}
+	lines = append(lines, "Outputs:")
+	if len(svc.Outputs) == 0 {
+		lines = append(lines, "  (none)")
+	}
+	for _, p := range svc.Outputs {
+		lines = append(lines, fmt.Sprintf("  %-20s %s", p.Name, p.Type))
+	}
+
+	for _, st := range b.snippets.usedStructs(svc) {
+		lines = append(lines, "", "type "+st.Name+" struct {")
+		for _, f := range st.Fields {
+			tag := ""
+			if f.JSONTag != "" {
+				tag = " `json:\"" + f.JSONTag + "\"`"
+			}
+			lines = append(lines, fmt.Sprintf("  %-18s %s%s", f.Name, f.Type, tag))
+		}
+		lines = append(lines, "}")
+	}
+	return lines
+}
+
+func countServices(n *node) int {
+	total := len(n.services)
+	for _, c := range n.children {
+		total += countServices(c)
+	}
+	return total
+}
+
+// truncate cuts s to width runes (the content never carries escape codes).
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
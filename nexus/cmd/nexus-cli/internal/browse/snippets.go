@@ -0,0 +1,202 @@
+package browse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
+	"github.com/japablazatww/nexus/nexus/search"
+)
+
+// Snippets renders ready-to-edit calls for a service, with zero values for
+// every parameter (structs are expanded field by field).
+type Snippets struct {
+	structs map[string]model.StructMetadata // "namespace.Name" -> struct
+}
+
+func NewSnippets(catalog model.Catalog) *Snippets {
+	s := &Snippets{structs: make(map[string]model.StructMetadata)}
+	for _, st := range catalog.Structs {
+		s.structs[st.Namespace+"."+st.Name] = st
+	}
+	return s
+}
+
+// Struct returns the catalog struct a type refers to, if any.
+func (s *Snippets) Struct(goType string, namespace string) (model.StructMetadata, bool) {
+	for _, name := range search.BaseTypeNames(goType) {
+		if st, ok := s.structs[namespace+"."+name]; ok {
+			return st, true
+		}
+	}
+	return model.StructMetadata{}, false
+}
+
+// Curl returns a curl command calling the service on baseURL.
+func (s *Snippets) Curl(svc model.ServiceEntry, baseURL string) string {
+	body := `{"params": ` + s.jsonParams(svc) + `}`
+	return fmt.Sprintf("curl -X POST %s/%s \\\n  -H 'Content-Type: application/json' \\\n  -d '%s'",
+		strings.TrimRight(baseURL, "/"), svc.Endpoint(), strings.ReplaceAll(body, "'", `'\''`))
+}
+
+// GoSDK returns a call through the generated Go SDK.
+func (s *Snippets) GoSDK(svc model.ServiceEntry, baseURL string) string {
+	var path []string
+	for _, p := range strings.Split(svc.Namespace, ".") {
+		path = append(path, util.ToPascalCase(strings.ReplaceAll(p, "-", "")))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "client := generated.NewClient(%q)\n", baseURL)
+	fmt.Fprintf(&sb, "resp, err := client.%s.%s(generated.GenericRequest{Params: map[string]interface{}{\n", strings.Join(path, "."), svc.Method)
+	for _, in := range svc.Inputs {
+		fmt.Fprintf(&sb, "\t%q: %s,\n", in.Name, s.goValue(in.Type, svc.Namespace, 1, nil))
+	}
+	sb.WriteString("}})")
+	return sb.String()
+}
+
+func (s *Snippets) jsonParams(svc model.ServiceEntry) string {
+	var parts []string
+	for _, in := range svc.Inputs {
+		parts = append(parts, strconv.Quote(in.Name)+": "+s.jsonValue(in.Type, svc.Namespace, nil))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// jsonValue renders the zero value of a type as JSON. seen stops recursion
+// on self-referencing structs.
+func (s *Snippets) jsonValue(goType string, namespace string, seen map[string]bool) string {
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return s.jsonValue(goType[1:], namespace, seen)
+	case strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "["):
+		return "[]"
+	case strings.HasPrefix(goType, "map["):
+		return "{}"
+	}
+	switch goType {
+	case "string", "time.Time":
+		return `""`
+	case "bool":
+		return "false"
+	}
+	if isNumeric(goType) {
+		return "0"
+	}
+
+	st, ok := s.structs[namespace+"."+goType]
+	if !ok || seen[goType] {
+		return "null"
+	}
+	seen = with(seen, goType)
+	var parts []string
+	for _, f := range wireFields(st) {
+		parts = append(parts, strconv.Quote(f.name)+": "+s.jsonValue(f.goType, namespace, seen))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// goValue renders the zero value of a type as a Go literal for GenericRequest.
+func (s *Snippets) goValue(goType string, namespace string, depth int, seen map[string]bool) string {
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return s.goValue(goType[1:], namespace, depth, seen)
+	case strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "["):
+		return "[]interface{}{}"
+	case strings.HasPrefix(goType, "map["):
+		return "map[string]interface{}{}"
+	}
+	switch goType {
+	case "string", "time.Time":
+		return `""`
+	case "bool":
+		return "false"
+	}
+	if isNumeric(goType) {
+		return "0"
+	}
+
+	st, ok := s.structs[namespace+"."+goType]
+	if !ok || seen[goType] {
+		return "nil"
+	}
+	seen = with(seen, goType)
+	indent := strings.Repeat("\t", depth)
+	var sb strings.Builder
+	sb.WriteString("map[string]interface{}{\n")
+	for _, f := range wireFields(st) {
+		fmt.Fprintf(&sb, "%s\t%q: %s,\n", indent, f.name, s.goValue(f.goType, namespace, depth+1, seen))
+	}
+	sb.WriteString(indent + "}")
+	return sb.String()
+}
+
+type snippetField struct {
+	name   string
+	goType string
+}
+
+// wireFields returns the wire names of a struct in declaration order,
+// skipping json:"-" fields.
+func wireFields(st model.StructMetadata) []snippetField {
+	var fields []snippetField
+	for _, f := range st.Fields {
+		name := strings.Split(f.JSONTag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, snippetField{name: name, goType: f.Type})
+	}
+	return fields
+}
+
+func isNumeric(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "byte", "rune":
+		return true
+	}
+	return false
+}
+
+func with(seen map[string]bool, name string) map[string]bool {
+	next := make(map[string]bool, len(seen)+1)
+	for k := range seen {
+		next[k] = true
+	}
+	next[name] = true
+	return next
+}
+
+// usedStructs lists the catalog structs a service takes or returns,
+// including nested ones, in order of appearance.
+func (s *Snippets) usedStructs(svc model.ServiceEntry) []model.StructMetadata {
+	seen := make(map[string]bool)
+	var out []model.StructMetadata
+	var visit func(goType string)
+	visit = func(goType string) {
+		st, ok := s.Struct(goType, svc.Namespace)
+		if !ok || seen[st.Name] {
+			return
+		}
+		seen[st.Name] = true
+		out = append(out, st)
+		for _, f := range st.Fields {
+			visit(f.Type)
+		}
+	}
+	for _, p := range svc.Inputs {
+		visit(p.Type)
+	}
+	for _, p := range svc.Outputs {
+		visit(p.Type)
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package browse
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// terminal switches the controlling terminal to raw mode with stty, so the
+// browser needs no terminal library.
+type terminal struct {
+	saved string
+}
+
+func openTerminal() (*terminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	// Alternate screen, hidden cursor
+	fmt.Fprint(os.Stdout, "\x1b[?1049h\x1b[?25l")
+	return &terminal{saved: strings.TrimSpace(saved)}, nil
+}
+
+func (t *terminal) restore() {
+	fmt.Fprint(os.Stdout, "\x1b[?25h\x1b[?1049l")
+	stty(t.saved)
+}
+
+// size returns the terminal width and height, 80x24 when unknown.
+func (t *terminal) size() (int, int) {
+	out, err := stty("size")
+	if err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscan(out, &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return cols, rows
+		}
+	}
+	return 80, 24
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
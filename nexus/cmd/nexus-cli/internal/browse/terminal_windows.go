@@ -0,0 +1,17 @@
+//go:build windows
+
+package browse
+
+import "errors"
+
+type terminal struct{}
+
+func openTerminal() (*terminal, error) {
+	return nil, errors.New("browse needs a Unix terminal (stty); use 'nexus-cli search' instead")
+}
+
+func (t *terminal) restore() {}
+
+func (t *terminal) size() (int, int) {
+	return 80, 24
+}
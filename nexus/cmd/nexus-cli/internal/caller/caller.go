@@ -0,0 +1,329 @@
+// Package caller builds and sends requests to a running Nexus server for
+// `nexus-cli call`, checking params against the catalog first.
+package caller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/japablazatww/nexus/nexus/model"
+	"github.com/japablazatww/nexus/nexus/search"
+)
+
+type Options struct {
+	BaseURL string
+	Headers map[string]string
+	Timeout time.Duration
+}
+
+// Response is what the server answered.
+type Response struct {
+	Status int
+	Body   []byte
+}
+
+// FindService resolves a full route ("libreria-b.loans.CalculateLoan") or a
+// unique method name ("CalculateLoan").
+func FindService(catalog model.Catalog, name string) (model.ServiceEntry, error) {
+	var matches []model.ServiceEntry
+	for _, svc := range catalog.Services {
+		if svc.Namespace+"."+svc.Method == name || svc.Endpoint() == name {
+			return svc, nil
+		}
+		if svc.Method == name {
+			matches = append(matches, svc)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		var suggestions []string
+		for _, res := range search.Fuzzy(catalog, name, 3) {
+			suggestions = append(suggestions, res.Namespace+"."+res.Method)
+		}
+		if len(suggestions) > 0 {
+			return model.ServiceEntry{}, fmt.Errorf("unknown service %s (did you mean %s?)", name, strings.Join(suggestions, ", "))
+		}
+		return model.ServiceEntry{}, fmt.Errorf("unknown service %s", name)
+	}
+	var routes []string
+	for _, svc := range matches {
+		routes = append(routes, svc.Namespace+"."+svc.Method)
+	}
+	return model.ServiceEntry{}, fmt.Errorf("ambiguous service %s: %s", name, strings.Join(routes, ", "))
+}
+
+// BuildParams merges a JSON document (either {"params": {...}} or the params
+// object itself) with key=value pairs and checks the result against the
+// service inputs. Pair values are coerced to the catalog type. A key may
+// name an input ("req"), a path into a struct input ("req.amount"), or a
+// field of the only struct input that has it ("amount"). Keys match the
+// way the server does: case and underscores are ignored.
+//
+// It returns the params and warnings for inputs left unset.
+func BuildParams(catalog model.Catalog, svc model.ServiceEntry, data []byte, pairs []string) (map[string]interface{}, []string, error) {
+	b := builder{catalog: catalog, svc: svc}
+
+	params := make(map[string]interface{})
+	if len(bytes.TrimSpace(data)) > 0 {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON input: %v", err)
+		}
+		if inner, ok := doc["params"].(map[string]interface{}); ok && len(doc) == 1 {
+			doc = inner
+		}
+		for k, v := range doc {
+			in, ok := b.input(k)
+			if !ok {
+				return nil, nil, b.unknown(k)
+			}
+			params[in.Name] = v
+		}
+	}
+
+	for _, pair := range pairs {
+		key, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid --param %q: expected key=value", pair)
+		}
+		if err := b.set(params, strings.TrimSpace(key), raw); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var warnings []string
+	for _, in := range svc.Inputs {
+		if _, ok := params[in.Name]; !ok {
+			warnings = append(warnings, fmt.Sprintf("input %s (%s) not set, the server will use its zero value", in.Name, in.Type))
+		}
+	}
+	return params, warnings, nil
+}
+
+// Call posts params to the service route.
+func Call(ctx context.Context, opts Options, route string, params map[string]interface{}) (*Response, error) {
+	body, err := json.Marshal(map[string]interface{}{"params": params})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(opts.BaseURL, "/")+"/"+route, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Status: resp.StatusCode, Body: respBody}, nil
+}
+
+// Pretty indents a JSON body; anything else is returned unchanged.
+func Pretty(body []byte) []byte {
+	var out bytes.Buffer
+	if err := json.Indent(&out, bytes.TrimSpace(body), "", "  "); err != nil {
+		return body
+	}
+	return out.Bytes()
+}
+
+type builder struct {
+	catalog model.Catalog
+	svc     model.ServiceEntry
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+func (b builder) input(key string) (model.ParamMetadata, bool) {
+	for _, in := range b.svc.Inputs {
+		if normalize(in.Name) == normalize(key) {
+			return in, true
+		}
+	}
+	return model.ParamMetadata{}, false
+}
+
+func (b builder) structOf(goType string) (model.StructMetadata, bool) {
+	name := strings.TrimPrefix(goType, "*")
+	for _, st := range b.catalog.Structs {
+		if st.Namespace == b.svc.Namespace && st.Name == name {
+			return st, true
+		}
+	}
+	return model.StructMetadata{}, false
+}
+
+// set assigns one key=value pair into params.
+func (b builder) set(params map[string]interface{}, key string, raw string) error {
+	path := strings.Split(key, ".")
+
+	in, ok := b.input(path[0])
+	if !ok {
+		// Shorthand: a field of the only struct input declaring it
+		var owners []model.ParamMetadata
+		for _, candidate := range b.svc.Inputs {
+			if st, isStruct := b.structOf(candidate.Type); isStruct {
+				if _, found := fieldByName(st, path[0]); found {
+					owners = append(owners, candidate)
+				}
+			}
+		}
+		switch len(owners) {
+		case 0:
+			return b.unknown(path[0])
+		case 1:
+			in = owners[0]
+			path = append([]string{in.Name}, path...)
+		default:
+			return fmt.Errorf("param %s is ambiguous: prefix it with one of the inputs (%s.%s, %s.%s, ...)", key, owners[0].Name, key, owners[1].Name, key)
+		}
+	}
+
+	if len(path) == 1 {
+		v, err := b.coerce(raw, in.Type)
+		if err != nil {
+			return fmt.Errorf("param %s: %v", in.Name, err)
+		}
+		params[in.Name] = v
+		return nil
+	}
+
+	// Walk struct fields, creating nested objects on the way
+	obj, _ := params[in.Name].(map[string]interface{})
+	if obj == nil {
+		obj = make(map[string]interface{})
+		params[in.Name] = obj
+	}
+	goType := in.Type
+	for i, name := range path[1:] {
+		st, ok := b.structOf(goType)
+		if !ok {
+			return fmt.Errorf("param %s: %s is not a struct", key, strings.Join(path[:i+1], "."))
+		}
+		field, ok := fieldByName(st, name)
+		if !ok {
+			return fmt.Errorf("param %s: %s has no field %s (fields: %s)", key, st.Name, name, strings.Join(fieldNames(st), ", "))
+		}
+		wire := wireName(field)
+		if i == len(path)-2 {
+			v, err := b.coerce(raw, field.Type)
+			if err != nil {
+				return fmt.Errorf("param %s: %v", key, err)
+			}
+			obj[wire] = v
+			return nil
+		}
+		next, _ := obj[wire].(map[string]interface{})
+		if next == nil {
+			next = make(map[string]interface{})
+			obj[wire] = next
+		}
+		obj, goType = next, field.Type
+	}
+	return nil
+}
+
+func (b builder) unknown(key string) error {
+	var names []string
+	for _, in := range b.svc.Inputs {
+		names = append(names, in.Name+" ("+in.Type+")")
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("unknown param %s: %s.%s takes no inputs", key, b.svc.Namespace, b.svc.Method)
+	}
+	return fmt.Errorf("unknown param %s: %s.%s takes %s", key, b.svc.Namespace, b.svc.Method, strings.Join(names, ", "))
+}
+
+func fieldByName(st model.StructMetadata, name string) (model.StructField, bool) {
+	for _, f := range st.Fields {
+		if normalize(f.Name) == normalize(name) || normalize(wireName(f)) == normalize(name) {
+			return f, true
+		}
+	}
+	return model.StructField{}, false
+}
+
+func fieldNames(st model.StructMetadata) []string {
+	var names []string
+	for _, f := range st.Fields {
+		names = append(names, wireName(f))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func wireName(f model.StructField) string {
+	if name := strings.Split(f.JSONTag, ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
+
+// coerce converts a command-line value to the JSON value for goType.
+// Enums use their underlying type; composite types (structs, slices, maps)
+// take a JSON literal.
+func (b builder) coerce(raw string, goType string) (interface{}, error) {
+	goType = strings.TrimPrefix(goType, "*")
+	for _, e := range b.catalog.Enums {
+		if e.Namespace == b.svc.Namespace && e.Name == goType {
+			goType = e.Type
+		}
+	}
+
+	switch goType {
+	case "string":
+		return raw, nil
+	case "bool":
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", raw)
+		}
+		return v, nil
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return v, nil
+	case "float32", "float64":
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return v, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return nil, fmt.Errorf("%s expects a JSON value: %v", goType, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,548 @@
+// Package dev implements `nexus-cli dev`: it watches local library
+// checkouts, re-analyzes the domains whose sources changed, regenerates
+// nexus/generated when the catalog changes, rebuilds the server against the
+// local checkouts and restarts it.
+//
+// The repository's go.mod is never touched: builds use a temporary copy
+// (go build -modfile) with a replace directive per watched library.
+package dev
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/analyzer"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/generator"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+type Options struct {
+	Libs       []string                 // Local library checkouts to watch
+	Registry   []analyzer.RegistryEntry // Discovery settings of known modules
+	ModuleRoot string                   // Directory of the Nexus go.mod
+	ServerPkg  string                   // Server main package, relative to ModuleRoot (e.g. ./nexus)
+	OutputDir  string                   // nexus/generated
+	Generators []string
+	Interval   time.Duration // Polling interval
+	Debug      bool
+}
+
+// library is one watched checkout and its analysis, cached per domain dir.
+type library struct {
+	entry   analyzer.RegistryEntry
+	root    string
+	layout  analyzer.Layout
+	results map[string]analyzer.DomainResult // Domain dir -> analysis
+}
+
+type session struct {
+	opts    Options
+	libs    []*library
+	base    map[string]*model.Catalog // Catalog of each library not being watched
+	order   []string                  // Library namespaces in catalog.json order
+	tmpDir  string
+	modFile string
+	binary  string
+	server  *server
+	catalog []byte // Last generated catalog, to skip regeneration
+}
+
+// Run watches until ctx is cancelled.
+func Run(ctx context.Context, opts Options) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	s := &session{opts: opts}
+
+	for _, path := range opts.Libs {
+		lib, err := s.openLibrary(path)
+		if err != nil {
+			return err
+		}
+		s.libs = append(s.libs, lib)
+		fmt.Printf("Watching %s (%s, discovery %s)\n", lib.root, lib.entry.Module, lib.entry.Discovery)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "nexus-dev")
+	if err != nil {
+		return err
+	}
+	s.tmpDir = tmpDir
+	defer os.RemoveAll(tmpDir)
+	defer s.stopServer()
+
+	if err := s.prepareModFile(); err != nil {
+		return err
+	}
+	s.loadBaseCatalog()
+
+	snapshot := s.snapshot()
+	s.update(nil)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nStopping dev mode...")
+			return nil
+		case <-ticker.C:
+		}
+
+		next := s.snapshot()
+		changed := diff(snapshot, next)
+		if len(changed) == 0 {
+			continue
+		}
+		// Wait for editors to finish writing before reacting.
+		for {
+			time.Sleep(opts.Interval)
+			settled := s.snapshot()
+			if len(diff(next, settled)) == 0 {
+				break
+			}
+			changed = mergeDirs(changed, diff(next, settled))
+			next = settled
+		}
+		snapshot = next
+		s.update(changed)
+	}
+}
+
+func (s *session) openLibrary(path string) (*library, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	module, err := analyzer.ReadModulePath(root)
+	if err != nil {
+		return nil, fmt.Errorf("library %s: %v", path, err)
+	}
+
+	entry := analyzer.RegistryEntry{Module: module, Discovery: analyzer.DiscoveryConfig}
+	for _, e := range s.opts.Registry {
+		if e.Module == module {
+			entry = e
+		}
+	}
+	return &library{entry: entry, root: root, results: make(map[string]analyzer.DomainResult)}, nil
+}
+
+// prepareModFile writes a copy of go.mod/go.sum that points every watched
+// module at its local checkout.
+func (s *session) prepareModFile() error {
+	goMod, err := os.ReadFile(filepath.Join(s.opts.ModuleRoot, "go.mod"))
+	if err != nil {
+		return err
+	}
+	s.modFile = filepath.Join(s.tmpDir, "go.mod")
+	if err := os.WriteFile(s.modFile, goMod, 0644); err != nil {
+		return err
+	}
+	if goSum, err := os.ReadFile(filepath.Join(s.opts.ModuleRoot, "go.sum")); err == nil {
+		os.WriteFile(filepath.Join(s.tmpDir, "go.sum"), goSum, 0644)
+	}
+
+	args := []string{"mod", "edit", "-modfile=" + s.modFile}
+	for _, lib := range s.libs {
+		if !bytes.Contains(goMod, []byte(lib.entry.Module+" ")) {
+			args = append(args, "-require="+lib.entry.Module+"@v0.0.0-00010101000000-000000000000")
+		}
+		args = append(args, "-replace="+lib.entry.Module+"="+lib.root)
+	}
+	cmd := exec.Command("go", args...)
+	cmd.Dir = s.opts.ModuleRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go mod edit: %v\n%s", err, out)
+	}
+
+	s.binary = filepath.Join(s.tmpDir, "nexus-server")
+	if runtime.GOOS == "windows" {
+		s.binary += ".exe"
+	}
+	return nil
+}
+
+// loadBaseCatalog keeps the services of every library that is not watched,
+// from the catalog.json of the last build.
+func (s *session) loadBaseCatalog() {
+	data, err := os.ReadFile(filepath.Join(s.opts.OutputDir, "catalog.json"))
+	if err != nil {
+		fmt.Printf("Warning: no catalog.json in %s; only the watched libraries will be served\n", s.opts.OutputDir)
+		return
+	}
+	s.catalog = data
+	var full model.Catalog
+	if err := json.Unmarshal(data, &full); err != nil {
+		fmt.Printf("Warning: invalid catalog.json: %v\n", err)
+		return
+	}
+
+	s.base = make(map[string]*model.Catalog)
+	for _, svc := range full.Services {
+		if c := s.baseFor(svc.Namespace); c != nil {
+			c.Services = append(c.Services, svc)
+		}
+	}
+	for _, st := range full.Structs {
+		if c := s.baseFor(st.Namespace); c != nil {
+			c.Structs = append(c.Structs, st)
+		}
+	}
+	for _, e := range full.Enums {
+		if c := s.baseFor(e.Namespace); c != nil {
+			c.Enums = append(c.Enums, e)
+		}
+	}
+	for _, t := range full.Types {
+		if c := s.baseFor(t.Namespace); c != nil {
+			c.Types = append(c.Types, t)
+		}
+	}
+	for _, d := range full.Domains {
+		if c := s.baseFor(d.Namespace); c != nil {
+			c.Domains = append(c.Domains, d)
+		}
+	}
+	for _, h := range full.HealthChecks {
+		if c := s.baseFor(h.Namespace); c != nil {
+			c.HealthChecks = append(c.HealthChecks, h)
+		}
+	}
+}
+
+// baseFor returns the base catalog of the library owning namespace, or nil
+// when that library is watched. It records the library order, so that the
+// regenerated files only differ where the sources did.
+func (s *session) baseFor(namespace string) *model.Catalog {
+	lib := strings.SplitN(namespace, ".", 2)[0]
+	if !containsString(s.order, lib) {
+		s.order = append(s.order, lib)
+	}
+	for _, l := range s.libs {
+		if l.namespace() == lib {
+			return nil
+		}
+	}
+	if s.base[lib] == nil {
+		s.base[lib] = &model.Catalog{}
+	}
+	return s.base[lib]
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *library) namespace() string {
+	return filepath.Base(l.entry.Module)
+}
+
+// update re-analyzes the domains in changed (every domain when nil),
+// regenerates the code if the catalog changed, and rebuilds the server.
+func (s *session) update(changed map[string]bool) {
+	start := time.Now()
+	if changed != nil {
+		fmt.Printf("\n[%s] Change detected in %s\n", start.Format("15:04:05"), describeDirs(changed))
+	}
+
+	parts := make(map[string]*model.Catalog)
+	for ns, c := range s.base {
+		parts[ns] = c
+	}
+	order := s.order
+	var metadata []model.FunctionMetadata
+
+	for _, lib := range s.libs {
+		ns := lib.namespace()
+		if !containsString(order, ns) {
+			order = append(order, ns)
+		}
+		part := &model.Catalog{}
+		parts[ns] = part
+		if lib.entry.Discovery == analyzer.DiscoveryAuto {
+			lib.layout = analyzer.PlanAuto(lib.root, ns, lib.entry.Module, lib.entry.Include, lib.entry.Exclude, s.opts.Debug)
+		} else {
+			lib.layout = analyzer.PlanLibrary(lib.root, ns, lib.entry.Module, s.opts.Debug)
+		}
+
+		results := make(map[string]analyzer.DomainResult)
+		for _, d := range lib.layout.Domains {
+			res, cached := lib.results[d.Dir]
+			if !cached || changed == nil || changed[d.Dir] {
+				res = analyzer.ParseDomain(d, s.opts.Debug)
+				if changed != nil {
+					fmt.Printf("  Re-analyzed %s (%d services)\n", d.Namespace, len(res.Services))
+				}
+			}
+			results[d.Dir] = res
+			res.AddTo(part, &metadata)
+		}
+		lib.results = results
+		part.Domains = append(part.Domains, lib.layout.Infos...)
+	}
+
+	var catalog model.Catalog
+	for _, ns := range order {
+		if part := parts[ns]; part != nil {
+			catalog.Services = append(catalog.Services, part.Services...)
+			catalog.Structs = append(catalog.Structs, part.Structs...)
+			catalog.Enums = append(catalog.Enums, part.Enums...)
+			catalog.Types = append(catalog.Types, part.Types...)
+			catalog.Domains = append(catalog.Domains, part.Domains...)
+			catalog.HealthChecks = append(catalog.HealthChecks, part.HealthChecks...)
+		}
+	}
+
+	if err := s.generate(catalog, metadata); err != nil {
+		fmt.Printf("Error generating code: %v\n", err)
+		return
+	}
+	if !s.build() {
+		return
+	}
+	s.restartServer()
+	fmt.Printf("Ready in %s (%d services)\n", time.Since(start).Round(time.Millisecond), len(catalog.Services))
+}
+
+// generate runs the generators when the catalog differs from the last run.
+func (s *session) generate(catalog model.Catalog, metadata []model.FunctionMetadata) error {
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if bytes.Equal(data, s.catalog) {
+		if s.opts.Debug {
+			fmt.Println("DEBUG: Catalog unchanged, skipping code generation")
+		}
+		return nil
+	}
+
+	before := hashFiles(s.opts.OutputDir)
+	if err := os.WriteFile(filepath.Join(s.opts.OutputDir, "catalog.json"), data, 0644); err != nil {
+		return err
+	}
+	ctx := generator.Context{Catalog: catalog, Metadata: metadata, OutputDir: s.opts.OutputDir}
+	for _, name := range s.opts.Generators {
+		g, ok := generator.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown generator %q", name)
+		}
+		if err := g.Generate(ctx); err != nil {
+			return fmt.Errorf("generator %s: %v", name, err)
+		}
+	}
+	s.catalog = data
+
+	after := hashFiles(s.opts.OutputDir)
+	var updated []string
+	for name, sum := range after {
+		if before[name] != sum {
+			updated = append(updated, name)
+		}
+	}
+	sort.Strings(updated)
+	if len(updated) > 0 {
+		fmt.Printf("  Regenerated %s\n", strings.Join(updated, ", "))
+	}
+	return nil
+}
+
+// build compiles the server against the local checkouts and prints compile
+// errors inline. The running server is kept when the build fails.
+func (s *session) build() bool {
+	cmd := exec.Command("go", "build", "-mod=mod", "-modfile="+s.modFile, "-o", s.binary, s.opts.ServerPkg)
+	cmd.Dir = s.opts.ModuleRoot
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Println("Build failed:")
+		for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+			fmt.Println("  | " + line)
+		}
+		if s.server != nil {
+			fmt.Println("Keeping the previous server running. Waiting for changes...")
+		} else {
+			fmt.Println("Waiting for changes...")
+		}
+		return false
+	}
+	return true
+}
+
+func (s *session) restartServer() {
+	s.stopServer()
+	srv, err := startServer(s.binary, s.opts.ModuleRoot)
+	if err != nil {
+		fmt.Printf("Error starting server: %v\n", err)
+		return
+	}
+	s.server = srv
+}
+
+func (s *session) stopServer() {
+	if s.server != nil {
+		s.server.stop(5 * time.Second)
+		s.server = nil
+	}
+}
+
+// --- Server process ---
+
+type server struct {
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	stopping atomic.Bool
+}
+
+func startServer(binary string, dir string) (*server, error) {
+	cmd := exec.Command(binary)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "NEXUS_SHUTDOWN_DRAIN_DELAY=0s") // Restarts do not wait for load balancers
+	cmd.Stdout = &prefixWriter{w: os.Stdout, prefix: "[server] "}
+	cmd.Stderr = &prefixWriter{w: os.Stderr, prefix: "[server] "}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	srv := &server{cmd: cmd, exited: make(chan struct{})}
+	go func() {
+		err := cmd.Wait()
+		if !srv.stopping.Load() {
+			fmt.Printf("Server exited: %v. Waiting for changes...\n", err)
+		}
+		close(srv.exited)
+	}()
+	return srv, nil
+}
+
+// stop asks the server to exit and kills it after grace.
+func (s *server) stop(grace time.Duration) {
+	s.stopping.Store(true)
+	if runtime.GOOS == "windows" {
+		s.cmd.Process.Kill()
+	} else {
+		s.cmd.Process.Signal(os.Interrupt)
+	}
+	select {
+	case <-s.exited:
+	case <-time.After(grace):
+		s.cmd.Process.Kill()
+		<-s.exited
+	}
+}
+
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		fmt.Fprintf(p.w, "%s%s\n", p.prefix, p.buf[:i])
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// --- Change detection ---
+
+// snapshot fingerprints every folder of the watched libraries: the name,
+// size and modification time of its Go sources, lib_config.json and go.mod.
+func (s *session) snapshot() map[string]string {
+	dirs := make(map[string]string)
+	for _, lib := range s.libs {
+		filepath.WalkDir(lib.root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != lib.root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			name := d.Name()
+			if !(strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")) && name != "lib_config.json" && name != "go.mod" {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			dir := filepath.Dir(path)
+			dirs[dir] += fmt.Sprintf("%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+	}
+	return dirs
+}
+
+func diff(a, b map[string]string) map[string]bool {
+	changed := make(map[string]bool)
+	for dir, fp := range b {
+		if a[dir] != fp {
+			changed[dir] = true
+		}
+	}
+	for dir := range a {
+		if _, ok := b[dir]; !ok {
+			changed[dir] = true
+		}
+	}
+	return changed
+}
+
+func mergeDirs(a, b map[string]bool) map[string]bool {
+	for dir := range b {
+		a[dir] = true
+	}
+	return a
+}
+
+func describeDirs(dirs map[string]bool) string {
+	var names []string
+	for dir := range dirs {
+		names = append(names, dir)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func hashFiles(dir string) map[string][32]byte {
+	sums := make(map[string][32]byte)
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, e.Name())); err == nil {
+			sums[e.Name()] = sha256.Sum256(data)
+		}
+	}
+	return sums
+}
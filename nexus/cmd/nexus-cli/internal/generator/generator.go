@@ -5,27 +5,43 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
-	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/model"
 	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
 )
 
 func GenerateServer(catalog model.Catalog, metadata []model.FunctionMetadata, outputDir string) error {
+	if err := CheckEndpoints(catalog); err != nil {
+		return err
+	}
+
 	imports := make(map[string]string) // path -> alias
 
+	type InputData struct {
+		Name   string
+		Type   string // As in the catalog
+		GoType string // Qualified for the generated package: libreria_b_loans.LoanRequest
+	}
+
 	type HandlerData struct {
+		Namespace  string
 		Route      string
 		FuncAlias  string
 		FuncName   string
-		Inputs     []model.ParamMetadata
+		Inputs     []InputData
 		Outputs    []model.ParamMetadata
 		HasError   bool
 		NumReturns int
+		Validation string // Checks run before the call; see validate.go
+		Flatten    bool
+		FlatFields []string // JSON names of the fields of the only input
 	}
 
 	handlers := []HandlerData{}
+	validators := newValidatorGen(catalog)
 
 	for _, svc := range catalog.Services {
 		importPath := svc.ImportPath
@@ -43,17 +59,52 @@ func GenerateServer(catalog model.Catalog, metadata []model.FunctionMetadata, ou
 			}
 		}
 
+		var inputs []InputData
+		for _, in := range svc.Inputs {
+			for _, pkg := range typePackages(in.Type) {
+				if !serverPackages[pkg] {
+					fmt.Fprintf(os.Stderr, "Warning: %s.%s: param %s uses package %s, which server_gen.go does not import\n", svc.Namespace, svc.Method, in.Name, pkg)
+				}
+			}
+			inputs = append(inputs, InputData{Name: in.Name, Type: in.Type, GoType: qualifyType(in.Type, alias)})
+		}
+
+		flatFields := flatFields(catalog, svc)
+		svc.Flatten = len(flatFields) > 0
 		handlers = append(handlers, HandlerData{
-			Route:      svc.Namespace + "." + svc.Method,
+			Namespace:  svc.Namespace,
+			Route:      svc.Endpoint(),
 			FuncAlias:  alias,
 			FuncName:   svc.Method,
-			Inputs:     svc.Inputs,
+			Inputs:     inputs,
 			Outputs:    svc.Outputs,
 			HasError:   hasError,
 			NumReturns: len(svc.Outputs),
+			Validation: validators.Params(svc),
+			Flatten:    svc.Flatten,
+			FlatFields: flatFields,
 		})
 	}
 
+	type HealthCheckData struct {
+		Namespace string
+		Alias     string
+	}
+	var healthChecks []HealthCheckData
+	for _, h := range catalog.HealthChecks {
+		alias := importAlias(h.Namespace)
+		imports[h.ImportPath] = alias
+		healthChecks = append(healthChecks, HealthCheckData{Namespace: h.Namespace, Alias: alias})
+	}
+
+	validatorFuncs := validators.Funcs()
+	for _, w := range validators.warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	if err := validators.Err(); err != nil {
+		return err
+	}
+
 	f, err := os.Create(filepath.Join(outputDir, "server_gen.go"))
 	if err != nil {
 		return err
@@ -61,11 +112,74 @@ func GenerateServer(catalog model.Catalog, metadata []model.FunctionMetadata, ou
 	defer f.Close()
 
 	return executeTemplate(f, ServerTemplate, map[string]interface{}{
-		"Imports":  imports,
-		"Handlers": handlers,
+		"HealthChecks": healthChecks,
+		"Imports":      imports,
+		"Handlers":     handlers,
+		"Validators":   validatorFuncs,
+		"UsesUTF8":     validators.usesLen,
 	})
 }
 
+// flatFields returns the JSON names of the fields a flattened service
+// accepts at the top level of params; none when it is not flattened.
+func flatFields(catalog model.Catalog, svc model.ServiceEntry) []string {
+	if !svc.Flatten {
+		return nil
+	}
+	var names []string
+	for _, s := range catalog.Structs {
+		if s.Namespace == svc.Namespace && s.Name == svc.Inputs[0].Type {
+			for _, f := range s.Fields {
+				if name, _, skip := jsonFieldName(f); !skip {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// CheckEndpoints reports two services served at the same route, which
+// happens when a routePrefix sends a domain under the routes of another.
+// The generated server would panic registering the second one.
+func CheckEndpoints(catalog model.Catalog) error {
+	owners := make(map[string]string) // endpoint -> namespace
+	for _, svc := range catalog.Services {
+		endpoint := svc.Endpoint()
+		if prev, ok := owners[endpoint]; ok {
+			return fmt.Errorf("route collision: /%s is served by both %s and %s", endpoint, prev, svc.Namespace)
+		}
+		owners[endpoint] = svc.Namespace
+	}
+	return nil
+}
+
+// serverPackages are the packages server_gen.go always imports, so params
+// may use their types (time.Duration, *big.Int, json.RawMessage).
+var serverPackages = map[string]bool{"time": true, "big": true, "json": true}
+
+// stdPackagePaths are the import paths of serverPackages.
+var stdPackagePaths = map[string]string{"time": "time", "big": "math/big", "json": "encoding/json"}
+
+// ServerImports reports whether server_gen.go imports the package named
+// pkg, so signatures may use its types. 'nexus-cli lint' checks qualified
+// types with it.
+func ServerImports(pkg string) bool {
+	return serverPackages[pkg]
+}
+
+// typePackages returns the package qualifiers in a catalog type:
+// map[string]*big.Int -> [big].
+func typePackages(goType string) []string {
+	var pkgs []string
+	for _, part := range strings.FieldsFunc(goType, func(r rune) bool { return strings.ContainsRune("[]*", r) }) {
+		if pkg, _, ok := strings.Cut(part, "."); ok {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs
+}
+
 func GenerateSDK(catalog model.Catalog, outputDir string) error {
 	// Tree structure
 	type Node struct {
@@ -102,6 +216,16 @@ func GenerateSDK(catalog model.Catalog, outputDir string) error {
 
 	var structs []StructDef
 
+	// Children in name order, so regenerated files diff cleanly
+	childNames := func(n *Node) []string {
+		names := make([]string, 0, len(n.Children))
+		for name := range n.Children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
 	// BFS or DFS to traverse and build structs
 	var traverse func(n *Node, prefix string) string // returns TypeName
 	traverse = func(n *Node, prefix string) string {
@@ -123,8 +247,8 @@ func GenerateSDK(catalog model.Catalog, outputDir string) error {
 			nextPrefix = prefix + n.Name
 		}
 
-		for childName, childNode := range n.Children {
-			childType := traverse(childNode, nextPrefix)
+		for _, childName := range childNames(n) {
+			childType := traverse(n.Children[childName], nextPrefix)
 			myStruct.Fields = append(myStruct.Fields, fmt.Sprintf("%s *%s", childName, childType))
 		}
 
@@ -139,7 +263,8 @@ func GenerateSDK(catalog model.Catalog, outputDir string) error {
 	var initLines []string
 	var genInit func(n *Node, accessPath string, typePrefix string)
 	genInit = func(n *Node, accessPath string, typePrefix string) {
-		for childName, childNode := range n.Children {
+		for _, childName := range childNames(n) {
+			childNode := n.Children[childName]
 			childAccess := accessPath + "." + childName
 			childType := typePrefix + childName + "Client"
 
@@ -162,13 +287,97 @@ func GenerateSDK(catalog model.Catalog, outputDir string) error {
 	return executeSDKTemplate(f, SDKTemplate, structs, initCode)
 }
 
+// GenerateTransports writes transport_gen.go: an in-process Transport that
+// calls the server wrappers, and a programmable MockTransport. It needs the
+// files written by GenerateServer and GenerateSDK.
+func GenerateTransports(catalog model.Catalog, outputDir string) error {
+	type MethodData struct {
+		Name    string // Go SDK path + method: LibreriabLoansCalculateLoan
+		Route   string
+		Wrapper string
+	}
+
+	var methods []MethodData
+	for _, svc := range catalog.Services {
+		var name strings.Builder
+		for _, p := range strings.Split(svc.Namespace, ".") {
+			name.WriteString(util.ToPascalCase(strings.ReplaceAll(p, "-", "")))
+		}
+		name.WriteString(svc.Method)
+
+		methods = append(methods, MethodData{
+			Name:    name.String(),
+			Route:   svc.Endpoint(),
+			Wrapper: "wrapper" + importAlias(svc.Namespace) + "_" + svc.Method,
+		})
+	}
+
+	f, err := os.Create(filepath.Join(outputDir, "transport_gen.go"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return executeTemplate(f, TransportTemplate, map[string]interface{}{
+		"Methods": methods,
+	})
+}
+
+// GenerateTypes writes types_gen.go, a copy of the library structs. Enums
+// and named types are not copied: fields use the library's own through the
+// import alias of server_gen.go, or their underlying type when the
+// namespace has no services to import it from.
 func GenerateTypes(catalog model.Catalog, outputDir string) error {
+	paths := make(map[string]string) // namespace -> import path
+	for _, svc := range catalog.Services {
+		paths[svc.Namespace] = svc.ImportPath
+	}
+	named := make(map[string]string) // "namespace.Name" -> underlying type
+	for _, e := range catalog.Enums {
+		named[e.Namespace+"."+e.Name] = e.Type
+	}
+	for _, t := range catalog.Types {
+		named[t.Namespace+"."+t.Name] = t.Type
+	}
+
+	imports := make(map[string]string) // path -> alias, empty for the standard library
+	structs := make([]model.StructMetadata, 0, len(catalog.Structs))
+	for _, s := range catalog.Structs {
+		fields := make([]model.StructField, 0, len(s.Fields))
+		for _, field := range s.Fields {
+			field.Type = mapTypeNames(field.Type, func(name string) string {
+				if pkg, _, ok := strings.Cut(name, "."); ok {
+					if path, ok := stdPackagePaths[pkg]; ok {
+						imports[path] = ""
+					}
+					return name
+				}
+				underlying, ok := named[s.Namespace+"."+name]
+				if !ok {
+					return name
+				}
+				path, ok := paths[s.Namespace]
+				if !ok {
+					return underlying
+				}
+				imports[path] = importAlias(s.Namespace)
+				return importAlias(s.Namespace) + "." + name
+			})
+			fields = append(fields, field)
+		}
+		s.Fields = fields
+		structs = append(structs, s)
+	}
+
 	f, err := os.Create(filepath.Join(outputDir, "types_gen.go"))
 	if err != nil {
 		return err
 	}
 	defer f.Close()
-	return executeTemplate(f, TypesTemplate, catalog)
+	return executeTemplate(f, TypesTemplate, map[string]interface{}{
+		"Imports": imports,
+		"Structs": structs,
+	})
 }
 
 func executeTemplate(w io.Writer, tmplStr string, data interface{}) error {
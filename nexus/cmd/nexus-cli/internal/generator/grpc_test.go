@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/analyzer"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// bankImportPath is the import path of testdata/bank, the library the
+// generated server is built for.
+const bankImportPath = "github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/generator/testdata/bank"
+
+// TestGeneratedGRPCServer generates the server, the shared types and the
+// gRPC code of testdata/bank, and runs the tests kept in testdata against them with the
+// nexus_grpc tag, next to the codec round trip of nexus/generated.
+func TestGeneratedGRPCServer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a generated server with go test")
+	}
+	out, err := os.MkdirTemp("testdata", "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(out) })
+
+	var catalog model.Catalog
+	var metadata []model.FunctionMetadata
+	analyzer.CrawlLibrary(filepath.Join("testdata", "bank"), "bank", bankImportPath, &catalog, &metadata, false)
+
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "catalog.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateServer(catalog, metadata, out); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateProto(catalog, out); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateTypes(catalog, out); err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []string{
+		filepath.Join("testdata", "grpc_validation_test.go"),
+		filepath.Join("..", "..", "..", "..", "generated", "grpc_roundtrip_test.go"),
+	} {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(out, filepath.Base(src)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "test", "-tags", "nexus_grpc", "./"+filepath.ToSlash(out))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test %s: %v\n%s", out, err, output)
+	}
+}
@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// GenerateOpenAPI writes openapi.json describing every generated route.
+// Routes take the GenericRequest envelope: {"params": {...}}.
+func GenerateOpenAPI(catalog model.Catalog, outputDir string) error {
+	names := sdkTypeNames(catalog)
+
+	schemas := map[string]interface{}{
+		"ErrorResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error": map[string]interface{}{"type": "string"},
+			},
+		},
+		"RequestErrorResponse": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error": map[string]interface{}{"type": "string"},
+				"fields": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"field":   map[string]interface{}{"type": "string"},
+							"rule":    map[string]interface{}{"type": "string"},
+							"message": map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, e := range catalog.Enums {
+		schema := openAPISchema(e.Type, e.Namespace, names)
+		var values []interface{}
+		for _, v := range e.Values {
+			if v.Value == "" {
+				continue
+			}
+			if e.Type == "string" {
+				values = append(values, v.Value)
+			} else if n, err := strconv.ParseFloat(v.Value, 64); err == nil {
+				values = append(values, n)
+			}
+		}
+		if len(values) > 0 {
+			schema["enum"] = values
+		}
+		schemas[names[e.Namespace+"."+e.Name]] = schema
+	}
+
+	for _, s := range catalog.Structs {
+		props := make(map[string]interface{})
+		for _, f := range s.Fields {
+			name, _, skip := jsonFieldName(f)
+			if skip {
+				continue
+			}
+			props[name] = openAPISchema(f.Type, s.Namespace, names)
+		}
+		schemas[names[s.Namespace+"."+s.Name]] = map[string]interface{}{
+			"type":       "object",
+			"properties": props,
+		}
+	}
+
+	paths := make(map[string]interface{})
+	for _, svc := range catalog.Services {
+		props := make(map[string]interface{})
+		for _, in := range svc.Inputs {
+			props[in.Name] = openAPISchema(in.Type, svc.Namespace, names)
+		}
+		params := map[string]interface{}{
+			"type":       "object",
+			"properties": props,
+		}
+		if svc.Flatten {
+			// {"req": {...}} or the fields of req directly
+			params = map[string]interface{}{
+				"oneOf": []interface{}{params, openAPISchema(svc.Inputs[0].Type, svc.Namespace, names)},
+			}
+		}
+
+		ok := map[string]interface{}{"description": "OK"}
+		if rt := sdkResultType(svc); rt != "" {
+			ok["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": openAPISchema(rt, svc.Namespace, names)},
+			}
+		}
+
+		op := map[string]interface{}{
+			"operationId": svc.Namespace + "." + svc.Method,
+			"tags":        []string{svc.Namespace},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"type":     "object",
+							"required": []string{"params"},
+							"properties": map[string]interface{}{
+								"params": params,
+							},
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": ok,
+				"400": map[string]interface{}{
+					"description": "Invalid request body (text/plain) or invalid params",
+					"content": map[string]interface{}{
+						"text/plain":       map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+						"application/json": map[string]interface{}{"schema": schemaRef("RequestErrorResponse")},
+					},
+				},
+				"500": map[string]interface{}{
+					"description": "The library returned an error",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": schemaRef("ErrorResponse")},
+					},
+				},
+			},
+		}
+		if lines := commentLines(svc.Description); len(lines) > 0 {
+			op["summary"] = lines[0]
+			op["description"] = strings.Join(lines, "\n")
+		}
+
+		paths["/"+svc.Endpoint()] = map[string]interface{}{"post": op}
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Nexus API",
+			"version": "1.0.0",
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"url": "http://localhost:8080"},
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, "openapi.json"), append(data, '\n'), 0644)
+}
+
+func schemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// openAPISchema maps a Go type expression from the catalog to a schema.
+func openAPISchema(goType string, namespace string, names map[string]string) map[string]interface{} {
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		if elem == "byte" || elem == "uint8" {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": openAPISchema(elem, namespace, names)}
+	}
+	if elem, ok := strings.CutPrefix(goType, "*"); ok {
+		inner := openAPISchema(elem, namespace, names)
+		if _, isRef := inner["$ref"]; isRef {
+			return map[string]interface{}{"allOf": []interface{}{inner}, "nullable": true}
+		}
+		inner["nullable"] = true
+		return inner
+	}
+
+	switch goType {
+	case "string":
+		return map[string]interface{}{"type": "string"}
+	case "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case "int", "int64", "uint", "uint64":
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32", "byte", "rune":
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case "float64":
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case "float32":
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case "time.Time":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "time.Duration":
+		return map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "string", "example": "1m30s"},
+			map[string]interface{}{"type": "integer", "format": "int64", "description": "Nanoseconds"},
+		}}
+	case "big.Int":
+		return map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "integer"},
+			map[string]interface{}{"type": "string", "pattern": "^-?[0-9]+$"},
+		}}
+	case "big.Float", "big.Rat":
+		return map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "string"},
+		}}
+	}
+
+	if name, ok := names[namespace+"."+goType]; ok {
+		return schemaRef(name)
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,742 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// ProtoPackage is the proto package every generated service lives in.
+const ProtoPackage = "nexus"
+
+type protoField struct {
+	Name      string // proto field name
+	Number    int
+	ProtoType string // "string", "repeated LibreriabLoansLoanRequest", "map<string, int64>", ...
+	Comment   string // Trailing comment in nexus.proto: how a value without a proto type is encoded
+	GoName    string // Go struct field name
+	GoType    string // Go type, used when declaring synthetic messages
+	Local     string // Local variable holding the value (method results only)
+
+	value    protoValue  // The value, or the element of a repeated field or the value of a map
+	key      *protoValue // Key of a map
+	repeated bool
+	json     bool // Carried as a JSON string
+}
+
+// protoValue is one value of a proto field: a plain field, the element of
+// a repeated field, or the key or the value of a map.
+type protoValue struct {
+	Kind      string // string, bytes, bool, int, uint, double, float, time, enum, message
+	ProtoType string // "int64", "LibreriabLoansLoanRequest", ...
+	GoType    string // Qualified Go type: "int8", "bank_accounts.Cents", "*libreria_b_loans.LoanRequest"
+	Msg       string // Proto name of the message or enum
+	Comment   string
+}
+
+type protoMessage struct {
+	Name      string
+	GoType    string
+	Synthetic bool // Declared in grpc_gen.go (per-method params/results)
+	Fields    []protoField
+}
+
+type protoEnumValue struct {
+	Name   string
+	Number int
+	GoName string
+}
+
+type protoEnum struct {
+	Name   string
+	GoType string
+	Alias  string
+	Values []protoEnumValue
+}
+
+type protoMethod struct {
+	Name     string
+	Comments []string
+	Alias    string
+	Request  *protoMessage
+	Response *protoMessage
+	Lhs      string // "ret0, err" style assignment of the library call
+	Args     string
+	HasError bool
+	Validate bool // Runs the validate method of the HTTP request struct
+	Flatten  bool // validate takes the path of the only input
+}
+
+type protoService struct {
+	Name      string
+	Namespace string
+	Methods   []protoMethod
+	Skipped   []string // "Method: reason" for signatures proto cannot express
+}
+
+// protoBuilder resolves catalog types into proto messages and enums.
+type protoBuilder struct {
+	structs  map[string]model.StructMetadata // "namespace.Name" -> struct
+	enums    map[string]model.EnumMetadata   // "namespace.Name" -> enum
+	named    map[string]string               // "namespace.Name" -> underlying type of a named basic type
+	aliases  map[string]string               // namespace -> Go import alias
+	messages []*protoMessage
+	enumDefs []protoEnum
+	names    map[string]string // proto name -> owner, for collision detection
+
+	validators *validatorGen // Decides which methods validate; GenerateServer writes the checks
+}
+
+func GenerateProto(catalog model.Catalog, outputDir string) error {
+	b := &protoBuilder{
+		structs: make(map[string]model.StructMetadata),
+		enums:   make(map[string]model.EnumMetadata),
+		named:   make(map[string]string),
+		aliases: make(map[string]string),
+		names:   make(map[string]string),
+
+		validators: newValidatorGen(catalog),
+	}
+
+	imports := make(map[string]string) // path -> alias
+	for _, svc := range catalog.Services {
+		b.aliases[svc.Namespace] = importAlias(svc.Namespace)
+		imports[svc.ImportPath] = importAlias(svc.Namespace)
+	}
+
+	// 1. Enums and structs become top-level messages. Types in namespaces
+	// without services have no import path and cannot be referenced.
+	for _, e := range catalog.Enums {
+		if _, ok := b.aliases[e.Namespace]; !ok {
+			continue
+		}
+		b.enums[e.Namespace+"."+e.Name] = e
+	}
+	for _, s := range catalog.Structs {
+		if _, ok := b.aliases[s.Namespace]; !ok {
+			continue
+		}
+		b.structs[s.Namespace+"."+s.Name] = s
+	}
+	for _, t := range catalog.Types {
+		if _, ok := b.aliases[t.Namespace]; ok {
+			b.named[t.Namespace+"."+t.Name] = t.Type
+		}
+	}
+
+	for _, e := range catalog.Enums {
+		if _, ok := b.enums[e.Namespace+"."+e.Name]; !ok {
+			continue
+		}
+		if err := b.addEnum(e); err != nil {
+			return err
+		}
+	}
+	for _, s := range catalog.Structs {
+		if _, ok := b.structs[s.Namespace+"."+s.Name]; !ok {
+			continue
+		}
+		if err := b.addStruct(s); err != nil {
+			return err
+		}
+	}
+
+	// 2. One service per namespace, with per-method request/response messages
+	var services []*protoService
+	byNamespace := make(map[string]*protoService)
+	for _, svc := range catalog.Services {
+		ps, ok := byNamespace[svc.Namespace]
+		if !ok {
+			ps = &protoService{Name: protoName(svc.Namespace) + "Service", Namespace: svc.Namespace}
+			byNamespace[svc.Namespace] = ps
+			services = append(services, ps)
+		}
+
+		svc.Flatten = len(flatFields(catalog, svc)) > 0 // As served over HTTP
+		method, reason := b.buildMethod(svc)
+		if reason != "" {
+			ps.Skipped = append(ps.Skipped, fmt.Sprintf("%s: %s", svc.Method, reason))
+			continue
+		}
+		if err := b.claim(method.Request.Name, svc.Namespace+"."+svc.Method); err != nil {
+			return err
+		}
+		if err := b.claim(method.Response.Name, svc.Namespace+"."+svc.Method); err != nil {
+			return err
+		}
+		b.messages = append(b.messages, method.Request, method.Response)
+		ps.Methods = append(ps.Methods, method)
+	}
+
+	// Only namespaces whose Go package is actually referenced get imported
+	used := make(map[string]bool)
+	for _, s := range b.structs {
+		used[s.Namespace] = true
+	}
+	for _, e := range b.enums {
+		used[e.Namespace] = true
+	}
+	for _, ps := range services {
+		if len(ps.Methods) > 0 {
+			used[ps.Namespace] = true
+		}
+	}
+	for path, alias := range imports {
+		found := false
+		for ns := range used {
+			if b.aliases[ns] == alias {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(imports, path)
+		}
+	}
+
+	data := map[string]interface{}{
+		"Package":  ProtoPackage,
+		"Imports":  imports,
+		"Enums":    b.enumDefs,
+		"Messages": b.messages,
+		"Services": services,
+	}
+
+	fProto, err := os.Create(filepath.Join(outputDir, "nexus.proto"))
+	if err != nil {
+		return err
+	}
+	defer fProto.Close()
+	if err := executeTemplate(fProto, ProtoTemplate, data); err != nil {
+		return err
+	}
+
+	fGo, err := os.Create(filepath.Join(outputDir, "grpc_gen.go"))
+	if err != nil {
+		return err
+	}
+	defer fGo.Close()
+	return executeTemplate(fGo, GRPCTemplate, data)
+}
+
+func (b *protoBuilder) claim(name string, owner string) error {
+	if prev, ok := b.names[name]; ok {
+		return fmt.Errorf("proto name collision: %s is generated for both %s and %s", name, prev, owner)
+	}
+	b.names[name] = owner
+	return nil
+}
+
+func (b *protoBuilder) addEnum(e model.EnumMetadata) error {
+	name := protoName(e.Namespace) + e.Name
+	if err := b.claim(name, e.Namespace+"."+e.Name); err != nil {
+		return err
+	}
+	alias := b.aliases[e.Namespace]
+	prefix := protoConstName(name)
+	def := protoEnum{
+		Name:   name,
+		GoType: alias + "." + e.Name,
+		Alias:  alias,
+		Values: []protoEnumValue{{Name: prefix + "_UNSPECIFIED", Number: 0}},
+	}
+	for i, v := range e.Values {
+		def.Values = append(def.Values, protoEnumValue{
+			Name:   prefix + "_" + protoConstName(v.Name),
+			Number: i + 1,
+			GoName: alias + "." + v.Name,
+		})
+	}
+	b.enumDefs = append(b.enumDefs, def)
+	return nil
+}
+
+func (b *protoBuilder) addStruct(s model.StructMetadata) error {
+	name := protoName(s.Namespace) + s.Name
+	if err := b.claim(name, s.Namespace+"."+s.Name); err != nil {
+		return err
+	}
+	alias := b.aliases[s.Namespace]
+	msg := &protoMessage{Name: name, GoType: alias + "." + s.Name}
+	number := 1
+	for _, f := range s.Fields {
+		fieldName := strings.Split(f.JSONTag, ",")[0]
+		if fieldName == "-" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = util.ToSnakeCase(f.Name)
+		}
+		field, ok := b.resolve(f.Type, s.Namespace)
+		if !ok {
+			// Foreign types are carried as JSON; json.Marshal handles them
+			// through the library's own struct field.
+			field = protoField{ProtoType: "string", Comment: "JSON encoded", json: true}
+		}
+		field.Name = fieldName
+		field.Number = number
+		field.GoName = f.Name
+		msg.Fields = append(msg.Fields, field)
+		number++
+	}
+	b.messages = append(b.messages, msg)
+	return nil
+}
+
+// buildMethod returns the method description, or a reason why the
+// signature cannot be expressed in the generated server.
+func (b *protoBuilder) buildMethod(svc model.ServiceEntry) (protoMethod, string) {
+	alias := b.aliases[svc.Namespace]
+	base := protoName(svc.Namespace) + svc.Method
+
+	req := &protoMessage{Name: base + "Params", GoType: "pb" + base + "Params", Synthetic: true}
+	var args []string
+	for i, in := range svc.Inputs {
+		field, ok := b.resolve(in.Type, svc.Namespace)
+		if !ok {
+			return protoMethod{}, fmt.Sprintf("unsupported input type %s", in.Type)
+		}
+		field.Name = in.Name
+		field.Number = i + 1
+		field.GoName = protoGoName(in.Name)
+		req.Fields = append(req.Fields, field)
+		args = append(args, "p."+field.GoName)
+	}
+
+	resp := &protoMessage{Name: base + "Result", GoType: "pb" + base + "Result", Synthetic: true}
+	var lhs []string
+	hasError := false
+	for i, out := range svc.Outputs {
+		if out.Type == "error" && i == len(svc.Outputs)-1 {
+			hasError = true
+			lhs = append(lhs, "err")
+			continue
+		}
+		field, ok := b.resolve(out.Type, svc.Namespace)
+		if !ok {
+			return protoMethod{}, fmt.Sprintf("unsupported output type %s", out.Type)
+		}
+		field.Name = out.Name
+		field.Number = len(resp.Fields) + 1
+		field.GoName = protoGoName(out.Name)
+		field.Local = fmt.Sprintf("ret%d", i)
+		resp.Fields = append(resp.Fields, field)
+		lhs = append(lhs, field.Local)
+	}
+
+	return protoMethod{
+		Name:     svc.Method,
+		Comments: commentLines(svc.Description),
+		Alias:    alias,
+		Request:  req,
+		Response: resp,
+		Lhs:      strings.Join(lhs, ", "),
+		Args:     strings.Join(args, ", "),
+		HasError: hasError,
+		Validate: b.validators.Params(svc) != "",
+		Flatten:  svc.Flatten,
+	}, ""
+}
+
+// resolve maps a Go type expression from the catalog to its proto field
+// representation. Names without a package qualifier are looked up among
+// the structs, enums and named types of the given namespace. Slices and
+// maps of values proto can describe become repeated and map fields.
+func (b *protoBuilder) resolve(goType string, namespace string) (protoField, bool) {
+	if v, ok := b.value(goType, namespace); ok {
+		return protoField{ProtoType: v.ProtoType, Comment: v.Comment, GoType: v.GoType, value: v}, true
+	}
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		if v, ok := b.value(elem, namespace); ok {
+			return protoField{ProtoType: "repeated " + v.ProtoType, Comment: v.Comment, GoType: "[]" + v.GoType, value: v, repeated: true}, true
+		}
+	}
+	if key, val, ok := splitMapType(goType); ok {
+		k, keyOK := b.value(key, namespace)
+		v, valueOK := b.value(val, namespace)
+		switch {
+		case !keyOK || !valueOK:
+		case k.Kind == "string" || k.Kind == "bool" || k.Kind == "int" || k.Kind == "uint": // Proto map keys
+			return protoField{
+				ProtoType: "map<" + k.ProtoType + ", " + v.ProtoType + ">",
+				Comment:   v.Comment,
+				GoType:    "map[" + k.GoType + "]" + v.GoType,
+				value:     v,
+				key:       &k,
+			}, true
+		}
+	}
+	return b.jsonField(goType, b.aliases[namespace])
+}
+
+// value maps a type to a single proto value, or reports that proto has no
+// scalar, enum or message for it. time.Duration is carried as int64
+// nanoseconds and time.Time as an RFC 3339 string.
+func (b *protoBuilder) value(goType string, namespace string) (protoValue, bool) {
+	alias := b.aliases[namespace]
+	switch goType {
+	case "string":
+		return protoValue{Kind: "string", ProtoType: "string", GoType: goType}, true
+	case "[]byte", "[]uint8":
+		return protoValue{Kind: "bytes", ProtoType: "bytes", GoType: goType}, true
+	case "bool":
+		return protoValue{Kind: "bool", ProtoType: "bool", GoType: goType}, true
+	case "float64":
+		return protoValue{Kind: "double", ProtoType: "double", GoType: goType}, true
+	case "float32":
+		return protoValue{Kind: "float", ProtoType: "float", GoType: goType}, true
+	case "int", "int64":
+		return protoValue{Kind: "int", ProtoType: "int64", GoType: goType}, true
+	case "int8", "int16", "int32", "rune":
+		return protoValue{Kind: "int", ProtoType: "int32", GoType: goType}, true
+	case "uint", "uint64":
+		return protoValue{Kind: "uint", ProtoType: "uint64", GoType: goType}, true
+	case "uint8", "uint16", "uint32", "byte":
+		return protoValue{Kind: "uint", ProtoType: "uint32", GoType: goType}, true
+	case "time.Duration":
+		return protoValue{Kind: "int", ProtoType: "int64", GoType: goType, Comment: "nanoseconds"}, true
+	case "time.Time":
+		return protoValue{Kind: "time", ProtoType: "string", GoType: goType, Comment: "RFC 3339 timestamp"}, true
+	}
+
+	name, pointer := strings.CutPrefix(goType, "*")
+	if s, ok := b.structs[namespace+"."+name]; ok {
+		msg := protoName(s.Namespace) + s.Name
+		return protoValue{Kind: "message", ProtoType: msg, GoType: qualifyType(goType, alias), Msg: msg}, true
+	}
+	if pointer {
+		return protoValue{}, false
+	}
+	if e, ok := b.enums[namespace+"."+goType]; ok {
+		name := protoName(e.Namespace) + e.Name
+		return protoValue{Kind: "enum", ProtoType: name, GoType: alias + "." + goType, Msg: name}, true
+	}
+	if underlying, ok := b.named[namespace+"."+goType]; ok {
+		v, ok := b.value(underlying, namespace)
+		v.GoType = alias + "." + goType
+		return v, ok
+	}
+	return protoValue{}, false
+}
+
+// splitMapType splits map[K]V into K and V.
+func splitMapType(goType string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(goType, "map[")
+	if !ok {
+		return "", "", false
+	}
+	depth := 1
+	for i, r := range rest {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			return rest[:i], rest[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// jsonField carries a value proto cannot describe as a JSON string. Types
+// from other packages are rejected because the generated file cannot
+// import them.
+func (b *protoBuilder) jsonField(goType string, alias string) (protoField, bool) {
+	core := strings.TrimLeft(goType, "[]*")
+	if strings.Contains(core, ".") {
+		return protoField{}, false
+	}
+	return protoField{ProtoType: "string", Comment: "JSON encoded", GoType: qualifyType(goType, alias), json: true}, true
+}
+
+// qualifyType prefixes package-local type names with the import alias,
+// keeping pointer/slice/array/map markers and builtin types untouched.
+func qualifyType(goType string, alias string) string {
+	return mapTypeNames(goType, func(name string) string {
+		if isBuiltinType(name) || strings.Contains(name, ".") {
+			return name
+		}
+		return alias + "." + name
+	})
+}
+
+// mapTypeNames replaces each type name in a type expression with fn(name),
+// keeping pointer/slice/array/map markers.
+func mapTypeNames(goType string, fn func(name string) string) string {
+	if key, value, ok := splitMapType(goType); ok {
+		return "map[" + mapTypeNames(key, fn) + "]" + mapTypeNames(value, fn)
+	}
+	if strings.HasPrefix(goType, "map[") {
+		return goType
+	}
+	if rest, ok := strings.CutPrefix(goType, "*"); ok {
+		return "*" + mapTypeNames(rest, fn)
+	}
+	if strings.HasPrefix(goType, "[") {
+		if i := strings.Index(goType, "]"); i >= 0 {
+			return goType[:i+1] + mapTypeNames(goType[i+1:], fn)
+		}
+	}
+	return fn(goType)
+}
+
+func isBuiltinType(name string) bool {
+	switch name {
+	case "string", "bool", "error", "any", "interface{}",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128", "byte", "rune":
+		return true
+	}
+	return false
+}
+
+// importAlias mirrors the alias used by the HTTP server for a namespace.
+func importAlias(namespace string) string {
+	alias := strings.ReplaceAll(namespace, ".", "_")
+	return strings.ReplaceAll(alias, "-", "_")
+}
+
+// protoName converts a namespace into the PascalCase prefix used by the
+// SDK: libreria-a.transfers.national -> LibreriaaTransfersNational
+func protoName(namespace string) string {
+	var sb strings.Builder
+	for _, p := range strings.Split(namespace, ".") {
+		sb.WriteString(util.ToPascalCase(strings.ReplaceAll(p, "-", "")))
+	}
+	return sb.String()
+}
+
+// protoConstName converts a Go name into the UPPER_SNAKE_CASE of proto
+// enum values, keeping acronyms together: StatusOK -> STATUS_OK,
+// HTTPError -> HTTP_ERROR.
+func protoConstName(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
+
+// protoGoName converts a snake_case catalog name into an exported Go field
+// name: user_i_d -> UserID, result_0 -> Result0
+func protoGoName(name string) string {
+	var sb strings.Builder
+	for _, p := range strings.Split(name, "_") {
+		sb.WriteString(util.ToPascalCase(p))
+	}
+	return sb.String()
+}
+
+func commentLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(text), "\n")
+}
+
+// Encode returns the statements appending field v.<GoName> to b in the
+// generated pbEncode function.
+func (f protoField) Encode() string {
+	return strings.ReplaceAll(f.encode(), "\n", "\n\t")
+}
+
+func (f protoField) encode() string {
+	field := "v." + f.GoName
+	switch {
+	case f.json:
+		return fmt.Sprintf("b = pbAppendJSON(b, %d, %s)", f.Number, field)
+	case f.key != nil:
+		return fmt.Sprintf("for k, e := range %s {\n\tvar entry []byte\n\t%s\n\t%s\n\tb = pbAppendBytes(b, %d, entry)\n}",
+			field, f.key.encode("entry", 1, "k"), f.value.encode("entry", 2, "e"), f.Number)
+	case f.repeated && f.value.packedWire() != "":
+		return fmt.Sprintf("if len(%s) > 0 {\n\tvar packed []byte\n\tfor _, e := range %s {\n\t\tpacked = %s\n\t}\n\tb = pbAppendBytes(b, %d, packed)\n}",
+			field, field, f.value.appendPacked("packed", "e"), f.Number)
+	case f.repeated:
+		return fmt.Sprintf("for i := range %s {\n\t%s\n}", field, f.value.encode("b", f.Number, field+"[i]"))
+	}
+	return f.value.encode("b", f.Number, field)
+}
+
+// Decode returns the statements reading field v.<GoName> from b in the
+// generated pbDecode function. Repeated scalars are read packed or not, as
+// proto3 parsers must.
+func (f protoField) Decode() string {
+	return strings.ReplaceAll(f.decode(), "\n", "\n\t\t\t")
+}
+
+func (f protoField) decode() string {
+	field := "v." + f.GoName
+	appendTo := func(x string) string { return field + " = append(" + field + ", " + x + ")" }
+	switch {
+	case f.json:
+		return fmt.Sprintf("n, err = pbReadJSON(b, typ, &%s)", field)
+	case f.key != nil:
+		return fmt.Sprintf(`var entry []byte
+entry, n, err = pbReadBytes(b, typ)
+if err == nil {
+	var k %s
+	var e %s
+	err = pbDecodeFields(entry, func(num protowire.Number, typ protowire.Type, b []byte) (n int, err error) {
+		switch num {
+		case 1:
+			%s
+		case 2:
+			%s
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		return n, err
+	})
+	if err == nil {
+		if %s == nil {
+			%s = make(%s)
+		}
+		%s[k] = e
+	}
+}`, f.key.GoType, f.value.GoType,
+			strings.ReplaceAll(f.key.assign("b", "typ", "n", "k"), "\n", "\n\t\t\t"),
+			strings.ReplaceAll(f.value.assign("b", "typ", "n", "e"), "\n", "\n\t\t\t"),
+			field, field, f.GoType, field)
+	case f.repeated && f.value.packedWire() != "":
+		return fmt.Sprintf(`if typ == protowire.BytesType {
+	var packed []byte
+	packed, n, err = pbReadBytes(b, typ)
+	for len(packed) > 0 && err == nil {
+		var m int
+		%s
+		packed = packed[m:]
+	}
+} else {
+	%s
+}`, f.value.decode("packed", f.value.packedWire(), "m", appendTo), f.value.decode("b", "typ", "n", appendTo))
+	case f.repeated:
+		return f.value.decode("b", "typ", "n", appendTo)
+	}
+	return f.value.assign("b", "typ", "n", field)
+}
+
+// encode returns the statement appending the value expr as field num of buf.
+func (v protoValue) encode(buf string, num int, expr string) string {
+	switch v.Kind {
+	case "string":
+		return fmt.Sprintf("%s = pbAppendString(%s, %d, %s)", buf, buf, num, v.convert("string", expr))
+	case "bytes":
+		return fmt.Sprintf("%s = pbAppendBytes(%s, %d, %s)", buf, buf, num, expr)
+	case "bool":
+		return fmt.Sprintf("%s = pbAppendBool(%s, %d, %s)", buf, buf, num, v.convert("bool", expr))
+	case "int", "uint":
+		return fmt.Sprintf("%s = pbAppendVarint(%s, %d, uint64(%s))", buf, buf, num, expr)
+	case "double":
+		return fmt.Sprintf("%s = pbAppendDouble(%s, %d, %s)", buf, buf, num, v.convert("float64", expr))
+	case "float":
+		return fmt.Sprintf("%s = pbAppendFloat(%s, %d, %s)", buf, buf, num, v.convert("float32", expr))
+	case "time":
+		return fmt.Sprintf("%s = pbAppendTime(%s, %d, %s)", buf, buf, num, expr)
+	case "enum":
+		return fmt.Sprintf("%s = pbAppendVarint(%s, %d, pbEnumIndex%s(%s))", buf, buf, num, v.Msg, expr)
+	}
+	if strings.HasPrefix(v.GoType, "*") {
+		return fmt.Sprintf("if %s != nil {\n\t%s = pbAppendBytes(%s, %d, pbEncode%s(nil, %s))\n}", expr, buf, buf, num, v.Msg, expr)
+	}
+	return fmt.Sprintf("%s = pbAppendBytes(%s, %d, pbEncode%s(nil, &%s))", buf, buf, num, v.Msg, expr)
+}
+
+// decode returns the statements reading a value from buf, setting the
+// number of bytes read to n, and handing the value to set.
+func (v protoValue) decode(buf, typ, n string, set func(x string) string) string {
+	if v.Kind == "message" {
+		elem := strings.TrimPrefix(v.GoType, "*")
+		x := "*x"
+		if elem != v.GoType {
+			x = "x"
+		}
+		return fmt.Sprintf("var raw []byte\nraw, %s, err = pbReadBytes(%s, %s)\nif err == nil {\n\tx := new(%s)\n\terr = pbDecode%s(raw, x)\n\t%s\n}",
+			n, buf, typ, elem, v.Msg, set(x))
+	}
+	r := protoReaders[v.Kind]
+	x := "x"
+	switch {
+	case v.Kind == "enum":
+		x = "pbEnumValue" + v.Msg + "(x)"
+	case v.GoType != r.goType:
+		x = v.GoType + "(x)"
+	}
+	return fmt.Sprintf("var x %s\nx, %s, err = %s(%s, %s)\n%s", r.goType, n, r.fn, buf, typ, set(x))
+}
+
+// assign returns the statements reading a value from buf into target.
+func (v protoValue) assign(buf, typ, n, target string) string {
+	if r, ok := protoReaders[v.Kind]; ok && v.Kind != "enum" && v.GoType == r.goType {
+		return fmt.Sprintf("%s, %s, err = %s(%s, %s)", target, n, r.fn, buf, typ)
+	}
+	return v.decode(buf, typ, n, func(x string) string { return target + " = " + x })
+}
+
+// protoReaders are the runtime functions reading each kind of value, and
+// the Go type they return.
+var protoReaders = map[string]struct{ fn, goType string }{
+	"string": {"pbReadString", "string"},
+	"bytes":  {"pbReadBytes", "[]byte"},
+	"bool":   {"pbReadBool", "bool"},
+	"int":    {"pbReadVarint", "uint64"},
+	"uint":   {"pbReadVarint", "uint64"},
+	"enum":   {"pbReadVarint", "uint64"},
+	"double": {"pbReadDouble", "float64"},
+	"float":  {"pbReadFloat", "float32"},
+	"time":   {"pbReadTime", "time.Time"},
+}
+
+// convert converts expr of type v.GoType to goType when they differ, as
+// for named types.
+func (v protoValue) convert(goType, expr string) string {
+	if v.GoType == goType {
+		return expr
+	}
+	return goType + "(" + expr + ")"
+}
+
+// packedWire returns the wire type of the elements of a packed repeated
+// field of v, or "" when proto3 does not pack them.
+func (v protoValue) packedWire() string {
+	switch v.Kind {
+	case "bool", "int", "uint", "enum":
+		return "protowire.VarintType"
+	case "double":
+		return "protowire.Fixed64Type"
+	case "float":
+		return "protowire.Fixed32Type"
+	}
+	return ""
+}
+
+// appendPacked returns the expression appending expr, without a tag, to
+// the packed buffer buf.
+func (v protoValue) appendPacked(buf, expr string) string {
+	switch v.Kind {
+	case "bool":
+		return fmt.Sprintf("protowire.AppendVarint(%s, protowire.EncodeBool(%s))", buf, v.convert("bool", expr))
+	case "enum":
+		return fmt.Sprintf("protowire.AppendVarint(%s, pbEnumIndex%s(%s))", buf, v.Msg, expr)
+	case "double":
+		return fmt.Sprintf("protowire.AppendFixed64(%s, math.Float64bits(%s))", buf, v.convert("float64", expr))
+	case "float":
+		return fmt.Sprintf("protowire.AppendFixed32(%s, math.Float32bits(%s))", buf, v.convert("float32", expr))
+	}
+	return fmt.Sprintf("protowire.AppendVarint(%s, uint64(%s))", buf, expr)
+}
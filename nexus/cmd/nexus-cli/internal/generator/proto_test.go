@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/analyzer"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+func TestProtoConstName(t *testing.T) {
+	tests := map[string]string{
+		"StatusOK":        "STATUS_OK",
+		"StatusHTTPError": "STATUS_HTTP_ERROR",
+		"UserID":          "USER_ID",
+		"Level2Auth":      "LEVEL2_AUTH",
+		"BankAccounts":    "BANK_ACCOUNTS",
+	}
+	for name, want := range tests {
+		if got := protoConstName(name); got != want {
+			t.Errorf("protoConstName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestProtoTypes checks that the named types, slices, maps and times of
+// testdata/bank become typed proto fields, and no method is skipped.
+func TestProtoTypes(t *testing.T) {
+	var catalog model.Catalog
+	var metadata []model.FunctionMetadata
+	analyzer.CrawlLibrary(filepath.Join("testdata", "bank"), "bank", bankImportPath, &catalog, &metadata, false)
+
+	out := t.TempDir()
+	if err := GenerateProto(catalog, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "nexus.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto := string(data)
+	for _, want := range []string{
+		"int64 fee = 4;",
+		"BANK_ACCOUNTS_STATUS_STATUS_HTTP_ERROR = 2;",
+		"string opened = 3; // RFC 3339 timestamp",
+		"int64 hold = 4; // nanoseconds",
+		"repeated int64 entries = 5;",
+		"map<int32, int64> limits = 10;",
+		"map<string, BankAccountsTransferRequest> payments = 11;",
+		"repeated int64 ids = 4;",
+	} {
+		if !strings.Contains(proto, want) {
+			t.Errorf("nexus.proto does not contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"JSON encoded", "Skipped"} {
+		if strings.Contains(proto, unwanted) {
+			t.Errorf("nexus.proto contains %q:\n%s", unwanted, proto)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// pyType describes how a Go type is represented in the Python SDK.
+// Decode and Encode are format strings applied to a Python expression.
+type pyType struct {
+	Annotation string
+	Default    string
+	Decode     string
+	Encode     string
+}
+
+type pyField struct {
+	Attr       string
+	JSONName   string
+	Annotation string
+	Default    string
+	Decode     string // Python expression reading the value from `data`
+	Encode     string // Python expression reading the value from `self`
+}
+
+type pyClass struct {
+	Name   string
+	Fields []pyField
+}
+
+type pyEnum struct {
+	Name   string
+	Values []pyEnumValue
+}
+
+type pyEnumValue struct {
+	Name  string
+	Value string
+}
+
+type pyParam struct {
+	Attr       string
+	JSONName   string
+	Annotation string
+	Encode     string
+}
+
+type pyMethod struct {
+	Name       string
+	Route      string
+	Params     []pyParam
+	ResultType string
+	Decode     string // Python expression decoding `result`
+	Comments   []string
+}
+
+type pyClient struct {
+	Name     string
+	Children []pyClientField
+	Methods  []pyMethod
+}
+
+type pyClientField struct {
+	Attr string
+	Type string
+}
+
+// GeneratePythonSDK writes a dependency-free Python package (standard
+// library only) into outputDir/nexus_sdk, mirroring the Go SDK tree:
+// client.libreria_b.loans.calculate_loan(req=LoanRequest(...))
+func GeneratePythonSDK(catalog model.Catalog, outputDir string) error {
+	names := sdkTypeNames(catalog)
+	r := pyResolver{names: names, enums: make(map[string]string)}
+
+	var enums []pyEnum
+	for _, e := range catalog.Enums {
+		r.enums[e.Namespace+"."+e.Name] = e.Type
+		pe := pyEnum{Name: names[e.Namespace+"."+e.Name]}
+		for _, v := range e.Values {
+			if v.Value == "" {
+				continue
+			}
+			value := v.Value
+			if e.Type == "string" {
+				value = strconv.Quote(v.Value)
+			}
+			pe.Values = append(pe.Values, pyEnumValue{Name: strings.ToUpper(util.ToSnakeCase(v.Name)), Value: value})
+		}
+		enums = append(enums, pe)
+	}
+
+	var classes []pyClass
+	for _, s := range catalog.Structs {
+		class := pyClass{Name: names[s.Namespace+"."+s.Name]}
+		for _, f := range s.Fields {
+			jsonName, _, skip := jsonFieldName(f)
+			if skip {
+				continue
+			}
+			// Prefer the wire name (already snake_case by convention)
+			attr := pyIdentifier(util.ToSnakeCase(f.Name))
+			if jsonName != f.Name {
+				attr = pyIdentifier(jsonName)
+			}
+			t := r.typeOf(f.Type, s.Namespace)
+			class.Fields = append(class.Fields, pyField{
+				Attr:       attr,
+				JSONName:   jsonName,
+				Annotation: t.Annotation,
+				Default:    t.Default,
+				Decode:     fmt.Sprintf(t.Decode, fmt.Sprintf("data.get(%s, %s)", strconv.Quote(jsonName), t.missing())),
+				Encode:     fmt.Sprintf(t.Encode, "self."+attr),
+			})
+		}
+		classes = append(classes, class)
+	}
+
+	root := buildNamespaceTree(catalog)
+	var clients []pyClient
+	root.walk(func(n *sdkNode) {
+		client := pyClient{Name: sdkClientName(n)}
+		for _, c := range n.Children {
+			client.Children = append(client.Children, pyClientField{
+				Attr: pyIdentifier(strings.ReplaceAll(c.Segment, "-", "_")),
+				Type: sdkClientName(c),
+			})
+		}
+		for _, svc := range n.Methods {
+			method := pyMethod{
+				Name:       pyIdentifier(util.ToSnakeCase(svc.Method)),
+				Route:      svc.Endpoint(),
+				ResultType: "None",
+				Decode:     "None",
+				Comments:   commentLines(svc.Description),
+			}
+			for _, in := range svc.Inputs {
+				t := r.typeOf(in.Type, svc.Namespace)
+				attr := pyIdentifier(in.Name)
+				method.Params = append(method.Params, pyParam{
+					Attr:       attr,
+					JSONName:   in.Name,
+					Annotation: t.Annotation,
+					Encode:     fmt.Sprintf(t.Encode, attr),
+				})
+			}
+			if rt := sdkResultType(svc); rt != "" {
+				t := r.typeOf(rt, svc.Namespace)
+				method.ResultType = t.Annotation
+				method.Decode = fmt.Sprintf(t.Decode, "result")
+			}
+			client.Methods = append(client.Methods, method)
+		}
+		clients = append(clients, client)
+	})
+
+	pkgDir := filepath.Join(outputDir, "nexus_sdk")
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		return fmt.Errorf("could not create %s: %v", pkgDir, err)
+	}
+
+	var exported []string
+	for _, e := range enums {
+		exported = append(exported, e.Name)
+	}
+	for _, c := range classes {
+		exported = append(exported, c.Name)
+	}
+
+	data := map[string]interface{}{
+		"Enums":    enums,
+		"Classes":  classes,
+		"Clients":  clients,
+		"Exported": exported,
+	}
+	files := []struct {
+		path string
+		tmpl string
+	}{
+		{filepath.Join(outputDir, "pyproject.toml"), PyProjectTemplate},
+		{filepath.Join(pkgDir, "__init__.py"), PyInitTemplate},
+		{filepath.Join(pkgDir, "errors.py"), PyErrorsTemplate},
+		{filepath.Join(pkgDir, "models.py"), PyModelsTemplate},
+		{filepath.Join(pkgDir, "client.py"), PyClientTemplate},
+	}
+	for _, file := range files {
+		if err := writeTemplate(file.path, file.tmpl, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missing is the value used when a key is absent from a response.
+func (t pyType) missing() string {
+	if strings.HasPrefix(t.Default, "field(") {
+		return "None"
+	}
+	return t.Default
+}
+
+// pyResolver maps Go type expressions from the catalog to Python.
+type pyResolver struct {
+	names map[string]string // "namespace.Name" -> SDK name
+	enums map[string]string // "namespace.Name" -> underlying Go type
+}
+
+func (r pyResolver) typeOf(goType string, namespace string) pyType {
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		if elem == "byte" || elem == "uint8" {
+			return pyType{Annotation: "str", Default: `""`, Decode: "%s", Encode: "%s"} // base64
+		}
+		inner := r.typeOf(elem, namespace)
+		return pyType{
+			Annotation: "List[" + inner.Annotation + "]",
+			Default:    "field(default_factory=list)",
+			Decode:     "[" + fmt.Sprintf(inner.Decode, "e") + " for e in (%s or [])]",
+			Encode:     "[" + fmt.Sprintf(inner.Encode, "e") + " for e in (%s or [])]",
+		}
+	}
+	if elem, ok := strings.CutPrefix(goType, "*"); ok {
+		inner := r.typeOf(elem, namespace)
+		return pyType{
+			Annotation: "Optional[" + inner.Annotation + "]",
+			Default:    "None",
+			Decode:     "_optional(%s, lambda v: " + fmt.Sprintf(inner.Decode, "v") + ")",
+			Encode:     "_optional(%s, lambda v: " + fmt.Sprintf(inner.Encode, "v") + ")",
+		}
+	}
+
+	switch goType {
+	case "string", "time.Time":
+		return pyType{Annotation: "str", Default: `""`, Decode: "%s", Encode: "%s"}
+	case "bool":
+		return pyType{Annotation: "bool", Default: "False", Decode: "%s", Encode: "%s"}
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		return pyType{Annotation: "int", Default: "0", Decode: "%s", Encode: "%s"}
+	case "float32", "float64":
+		return pyType{Annotation: "float", Default: "0.0", Decode: "%s", Encode: "%s"}
+	}
+
+	// Enums are plain constants classes; values keep their underlying type
+	if underlying, ok := r.enums[namespace+"."+goType]; ok {
+		return r.typeOf(underlying, namespace)
+	}
+	name, ok := r.names[namespace+"."+goType]
+	if !ok {
+		return pyType{Annotation: "Any", Default: "None", Decode: "%s", Encode: "%s"}
+	}
+	return pyType{
+		Annotation: name,
+		Default:    "field(default_factory=lambda: " + name + "())",
+		Decode:     "_optional(%s, " + name + ".from_dict)",
+		Encode:     "_optional(%s, lambda v: v.to_dict())",
+	}
+}
+
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "else": true, "except": true,
+	"finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true,
+	"not": true, "or": true, "pass": true, "raise": true, "return": true,
+	"try": true, "while": true, "with": true, "yield": true, "self": true,
+}
+
+// pyIdentifier makes a name safe to use as a Python attribute or argument.
+func pyIdentifier(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	id := sb.String()
+	if pyKeywords[id] {
+		id += "_"
+	}
+	return id
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// Context is the input shared by every generator.
+type Context struct {
+	Catalog   model.Catalog
+	Metadata  []model.FunctionMetadata
+	OutputDir string // The 'nexus/generated' folder
+	SDKDir    string // Base folder for standalone SDK packages (<SDKDir>/<lang>)
+}
+
+// Generator produces one output (Go server, SDK, docs...) from the catalog.
+type Generator interface {
+	Name() string
+	Description() string
+	Generate(ctx Context) error
+}
+
+// DefaultGenerators is the selection used when --generators is not set.
+var DefaultGenerators = []string{"server", "sdk", "types", "transports"}
+
+var registry = make(map[string]Generator)
+
+// Register makes a generator selectable by name. A name can only be
+// registered once: a template directory named like a built-in generator
+// would otherwise replace it.
+func Register(g Generator) error {
+	if _, ok := registry[g.Name()]; ok {
+		return fmt.Errorf("generator %q is already registered", g.Name())
+	}
+	registry[g.Name()] = g
+	return nil
+}
+
+func mustRegister(g Generator) {
+	if err := Register(g); err != nil {
+		panic(err)
+	}
+}
+
+func Lookup(name string) (Generator, bool) {
+	g, ok := registry[name]
+	return g, ok
+}
+
+// Available returns every registered generator sorted by name.
+func Available() []Generator {
+	var gens []Generator
+	for _, g := range registry {
+		gens = append(gens, g)
+	}
+	sort.Slice(gens, func(i, j int) bool { return gens[i].Name() < gens[j].Name() })
+	return gens
+}
+
+type funcGenerator struct {
+	name        string
+	description string
+	fn          func(ctx Context) error
+}
+
+func (g funcGenerator) Name() string               { return g.name }
+func (g funcGenerator) Description() string        { return g.description }
+func (g funcGenerator) Generate(ctx Context) error { return g.fn(ctx) }
+
+func init() {
+	mustRegister(funcGenerator{"server", "Go HTTP server (server_gen.go)", func(ctx Context) error {
+		return GenerateServer(ctx.Catalog, ctx.Metadata, ctx.OutputDir)
+	}})
+	mustRegister(funcGenerator{"sdk", "Go SDK client (sdk_gen.go)", func(ctx Context) error {
+		return GenerateSDK(ctx.Catalog, ctx.OutputDir)
+	}})
+	mustRegister(funcGenerator{"types", "Go shared types (types_gen.go)", func(ctx Context) error {
+		return GenerateTypes(ctx.Catalog, ctx.OutputDir)
+	}})
+	mustRegister(funcGenerator{"transports", "In-process and mock SDK transports (transport_gen.go)", func(ctx Context) error {
+		return GenerateTransports(ctx.Catalog, ctx.OutputDir)
+	}})
+	mustRegister(funcGenerator{"proto", "nexus.proto and gRPC server (grpc_gen.go)", func(ctx Context) error {
+		return GenerateProto(ctx.Catalog, ctx.OutputDir)
+	}})
+	mustRegister(funcGenerator{"openapi", "OpenAPI 3 document (openapi.json)", func(ctx Context) error {
+		return GenerateOpenAPI(ctx.Catalog, ctx.OutputDir)
+	}})
+	mustRegister(funcGenerator{"sdk-ts", "TypeScript SDK package (<sdk-out>/ts)", func(ctx Context) error {
+		dir, err := sdkDir(ctx, "ts")
+		if err != nil {
+			return err
+		}
+		return GenerateTypeScriptSDK(ctx.Catalog, dir, "nexus-sdk")
+	}})
+	mustRegister(funcGenerator{"sdk-python", "Python SDK package (<sdk-out>/python)", func(ctx Context) error {
+		dir, err := sdkDir(ctx, "python")
+		if err != nil {
+			return err
+		}
+		return GeneratePythonSDK(ctx.Catalog, dir)
+	}})
+}
+
+func sdkDir(ctx Context, lang string) (string, error) {
+	base := ctx.SDKDir
+	if base == "" {
+		cwd, _ := os.Getwd()
+		base = filepath.Join(cwd, "sdk")
+	}
+	target := filepath.Join(base, lang)
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return "", fmt.Errorf("could not create SDK dir %s: %v", target, err)
+	}
+	return filepath.Abs(target)
+}
+
+// TemplateGenerator renders every *.tmpl file of a user directory with
+// text/template. Output paths mirror the directory layout without the
+// .tmpl suffix: docs/services.md.tmpl -> <output>/docs/services.md
+type TemplateGenerator struct {
+	Dir string
+}
+
+// NewTemplateGenerator validates dir and returns a generator named after it.
+func NewTemplateGenerator(dir string) (*TemplateGenerator, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	abs, _ := filepath.Abs(dir)
+	return &TemplateGenerator{Dir: abs}, nil
+}
+
+func (g *TemplateGenerator) Name() string {
+	return filepath.Base(g.Dir)
+}
+
+func (g *TemplateGenerator) Description() string {
+	return "User templates from " + g.Dir
+}
+
+func (g *TemplateGenerator) Generate(ctx Context) error {
+	data := map[string]interface{}{
+		"Catalog":  ctx.Catalog,
+		"Services": ctx.Catalog.Services,
+		"Structs":  ctx.Catalog.Structs,
+		"Enums":    ctx.Catalog.Enums,
+		"Metadata": ctx.Metadata,
+	}
+
+	return filepath.WalkDir(g.Dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".tmpl") {
+			return nil
+		}
+		rel, _ := filepath.Rel(g.Dir, path)
+		target := filepath.Join(ctx.OutputDir, strings.TrimSuffix(rel, ".tmpl"))
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t, err := template.New(rel).Funcs(TemplateFuncs).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %v", rel, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := t.Execute(f, data); err != nil {
+			return fmt.Errorf("%s: %v", rel, err)
+		}
+		return nil
+	})
+}
+
+// TemplateFuncs are available to user templates.
+var TemplateFuncs = template.FuncMap{
+	"snake":  util.ToSnakeCase,
+	"pascal": util.ToPascalCase,
+	"camel":  camelSegment,
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+	"join":   strings.Join,
+	"split":  strings.Split,
+	"replace": func(s, old, new string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"route": func(svc model.ServiceEntry) string {
+		return svc.Endpoint()
+	},
+}
@@ -0,0 +1,22 @@
+package generator
+
+import "testing"
+
+func TestRegisterDuplicate(t *testing.T) {
+	g, err := NewTemplateGenerator(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(g); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(g); err == nil {
+		t.Errorf("registering %s twice did not fail", g.Name())
+	}
+	if err := Register(funcGenerator{name: "server"}); err == nil {
+		t.Error("a generator replaced the built-in server generator")
+	}
+	if got, _ := Lookup("server"); got.Description() != "Go HTTP server (server_gen.go)" {
+		t.Errorf("server is now %q", got.Description())
+	}
+}
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// sdkNode is one level of the namespace tree used by the non-Go SDKs.
+// Children and methods keep a stable order so regenerated files diff cleanly.
+type sdkNode struct {
+	Segment   string // Raw namespace segment: "libreria-b"
+	Path      []string
+	Children  []*sdkNode
+	Methods   []model.ServiceEntry
+	childByID map[string]*sdkNode
+}
+
+func buildNamespaceTree(catalog model.Catalog) *sdkNode {
+	root := &sdkNode{childByID: make(map[string]*sdkNode)}
+	for _, svc := range catalog.Services {
+		current := root
+		for _, p := range strings.Split(svc.Namespace, ".") {
+			child, ok := current.childByID[p]
+			if !ok {
+				path := append(append([]string{}, current.Path...), p)
+				child = &sdkNode{Segment: p, Path: path, childByID: make(map[string]*sdkNode)}
+				current.childByID[p] = child
+				current.Children = append(current.Children, child)
+			}
+			current = child
+		}
+		current.Methods = append(current.Methods, svc)
+	}
+
+	var sortTree func(n *sdkNode)
+	sortTree = func(n *sdkNode) {
+		sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Segment < n.Children[j].Segment })
+		for _, c := range n.Children {
+			sortTree(c)
+		}
+	}
+	sortTree(root)
+	return root
+}
+
+// walk visits children before their parent, so nested clients are declared
+// before the clients that reference them.
+func (n *sdkNode) walk(fn func(*sdkNode)) {
+	for _, c := range n.Children {
+		c.walk(fn)
+	}
+	fn(n)
+}
+
+// sdkClientPrefix is the PascalCase path of a node: LibreriaBLoans
+func sdkClientPrefix(n *sdkNode) string {
+	var sb strings.Builder
+	for _, p := range n.Path {
+		sb.WriteString(util.ToPascalCase(camelSegment(p)))
+	}
+	return sb.String()
+}
+
+func sdkClientName(n *sdkNode) string {
+	if len(n.Path) == 0 {
+		return "Client"
+	}
+	return sdkClientPrefix(n) + "Client"
+}
+
+// camelSegment converts a namespace segment into a camelCase identifier:
+// libreria-b -> libreriaB, transfers -> transfers
+func camelSegment(segment string) string {
+	parts := strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return segment
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(parts[0][:1]) + parts[0][1:])
+	for _, p := range parts[1:] {
+		sb.WriteString(util.ToPascalCase(p))
+	}
+	return sb.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// jsonFieldName returns the wire name of a struct field following
+// encoding/json rules: the tag name when present, the Go name otherwise.
+func jsonFieldName(f model.StructField) (name string, omitEmpty bool, skip bool) {
+	parts := strings.Split(f.JSONTag, ",")
+	name = parts[0]
+	if name == "-" && len(parts) == 1 {
+		return "", false, true
+	}
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// sdkTypeNames assigns every catalog struct/enum a name unique across
+// namespaces. Bare names are kept unless two namespaces declare the same one.
+func sdkTypeNames(catalog model.Catalog) map[string]string {
+	count := make(map[string]int)
+	for _, s := range catalog.Structs {
+		count[s.Name]++
+	}
+	for _, e := range catalog.Enums {
+		count[e.Name]++
+	}
+
+	names := make(map[string]string) // "namespace.Name" -> SDK name
+	for _, s := range catalog.Structs {
+		names[s.Namespace+"."+s.Name] = uniqueTypeName(s.Namespace, s.Name, count)
+	}
+	for _, e := range catalog.Enums {
+		names[e.Namespace+"."+e.Name] = uniqueTypeName(e.Namespace, e.Name, count)
+	}
+	return names
+}
+
+func uniqueTypeName(namespace string, name string, count map[string]int) string {
+	if count[name] > 1 {
+		return protoName(namespace) + name
+	}
+	return name
+}
+
+// sdkResultType returns the catalog type the HTTP server sends back for a
+// service: the first non-error output, or "" when nothing is returned.
+func sdkResultType(svc model.ServiceEntry) string {
+	for _, out := range svc.Outputs {
+		if out.Type != "error" {
+			return out.Type
+		}
+	}
+	return ""
+}
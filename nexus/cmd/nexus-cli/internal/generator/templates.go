@@ -3,25 +3,601 @@ package generator
 const ServerTemplate = `package generated
 
 import (
+	"bytes"
+	_ "embed"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	{{if .UsesUTF8}}"unicode/utf8"
+	{{end}}
+	"github.com/japablazatww/nexus/nexus/discovery"
+	"github.com/japablazatww/nexus/nexus/metrics"
     
 	{{range $path, $alias := .Imports}}
 	{{$alias}} "{{$path}}"
 	{{end}}
 )
 
-func RegisterHandlers(mux *http.ServeMux) {
+// catalogJSON is the catalog this server was generated from, served by the
+// discovery API under /_nexus/.
+//go:embed catalog.json
+var catalogJSON []byte
+
+// MatchMode decides which keys of "params" bind to a function input.
+type MatchMode int
+
+const (
+	// MatchFuzzy ignores case and underscores: user_id, userId and USERID
+	// all bind to user_id. It is the default.
+	MatchFuzzy MatchMode = iota
+	// MatchSnakeCamel accepts the catalog name and its camelCase form:
+	// user_id or userId. Acronyms split per letter in the catalog are also
+	// accepted whole: user_i_d (userID) binds user_i_d, userID, user_id
+	// and userId.
+	MatchSnakeCamel
+	// MatchStrict only accepts the catalog name: user_id.
+	MatchStrict
+)
+
+// Option configures RegisterHandlers.
+type Option func(*serverOptions)
+
+type serverOptions struct {
+	match      MatchMode
+	services   map[string]MatchMode // Route -> mode
+	maxBody    int64
+	maxBodySet bool // WithMaxBodyBytes was given; gRPC keeps its own default otherwise
+	namespaces func(namespace string) bool
+	metrics    *metrics.Recorder
+}
+
+// WithMatchMode sets the matching mode of every service.
+func WithMatchMode(mode MatchMode) Option {
+	return func(o *serverOptions) {
+		o.match = mode
+	}
+}
+
+// WithServiceMatchMode overrides the matching mode of one service, by route
+// ("libreria-a.system.GetSystemStatus", or a Method* constant).
+func WithServiceMatchMode(route string, mode MatchMode) Option {
+	return func(o *serverOptions) {
+		if o.services == nil {
+			o.services = make(map[string]MatchMode)
+		}
+		o.services[route] = mode
+	}
+}
+
+// WithMaxBodyBytes rejects request bodies larger than n bytes with 413
+// Request Entity Too Large. 0 means no limit, the default.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *serverOptions) {
+		o.maxBody, o.maxBodySet = n, true
+	}
+}
+
+// WithNamespaceFilter only registers the services of the namespaces for
+// which enabled returns true. The discovery API lists only those services.
+func WithNamespaceFilter(enabled func(namespace string) bool) Option {
+	return func(o *serverOptions) {
+		o.namespaces = enabled
+	}
+}
+
+// WithMetrics records the requests of every route in m, labeled by
+// namespace and method; serve m at /metrics.
+func WithMetrics(m *metrics.Recorder) Option {
+	return func(o *serverOptions) {
+		o.metrics = m
+	}
+}
+
+func (o *serverOptions) instrument(namespace string, method string, h http.HandlerFunc) http.HandlerFunc {
+	if o.metrics == nil {
+		return h
+	}
+	return o.metrics.Instrument(namespace, method, h)
+}
+
+func (o *serverOptions) serves(namespace string) bool {
+	return o.namespaces == nil || o.namespaces(namespace)
+}
+
+func (o *serverOptions) matchModeFor(route string) MatchMode {
+	if mode, ok := o.services[route]; ok {
+		return mode
+	}
+	return o.match
+}
+
+// handlers serves the routes of one RegisterHandlers call with its
+// options, so servers in the same process keep their own settings.
+type handlers struct {
+	options serverOptions
+}
+
+func RegisterHandlers(mux *http.ServeMux, opts ...Option) {
+	h := &handlers{}
+	for _, opt := range opts {
+		opt(&h.options)
+	}
+
 	{{range .Handlers}}
-	mux.HandleFunc("/{{.Route}}", handle{{.FuncAlias}}_{{.FuncName}})
+	if h.options.serves("{{.Namespace}}") {
+		mux.HandleFunc("/{{.Route}}", h.options.instrument("{{.Namespace}}", "{{.FuncName}}", h.handle{{.FuncAlias}}_{{.FuncName}}))
+	}
 	{{end}}
+
+	catalog := catalogJSON
+	if h.options.namespaces != nil {
+		catalog = discovery.Filter(catalogJSON, h.options.serves)
+	}
+	discovery.Register(mux, catalog)
+}
+
+// ReadinessChecks returns the HealthCheck functions declared by the
+// library domains, by namespace. The server runs them to answer /readyz.
+func ReadinessChecks() map[string]func() error {
+	return map[string]func() error{
+		{{range .HealthChecks}}"{{.Namespace}}": {{.Alias}}.HealthCheck,
+		{{end}}
+	}
 }
 
+// FieldError is one invalid field of a request. Field is the path in
+// params, e.g. "req.amount" or "req.items[2].currency".
+type FieldError struct {
+	Field   string ` + "`" + `json:"field"` + "`" + `
+	Rule    string ` + "`" + `json:"rule"` + "`" + `
+	Message string ` + "`" + `json:"message"` + "`" + `
+}
+
+// RequestError rejects a call before it reaches the library. Handlers
+// answer it with 400 Bad Request:
+// {"error": "...", "fields": [{"field": "req.amount", ...}]}.
+type RequestError struct {
+	Fields []FieldError
+}
+
+func (e *RequestError) add(field, rule, message string) {
+	field = strings.TrimPrefix(field, ".") // Fields of a flattened struct
+	e.Fields = append(e.Fields, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+func (e *RequestError) Error() string {
+	var parts []string
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+" "+f.Message)
+	}
+	return "invalid params: " + strings.Join(parts, "; ")
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": reqErr.Error(), "fields": reqErr.Fields})
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// --- Parameter Binding ---
+
+// rawRequest is the body of every route. Params are kept raw so each value
+// is decoded once, straight into the type of its input.
+type rawRequest struct {
+	Params map[string]json.RawMessage ` + "`" + `json:"params"` + "`" + `
+}
+
+// paramTable binds the keys of "params" to the inputs of one function. The
+// lookup maps of every mode are built once, at init; -1 marks a key that
+// matches several inputs.
+type paramTable struct {
+	names  []string
+	byMode [3]map[string]int
+}
+
+func newParamTable(names ...string) *paramTable {
+	t := &paramTable{names: names}
+	for mode := range t.byMode {
+		lookup := make(map[string]int)
+		for i, name := range names {
+			for _, key := range matchKeys(MatchMode(mode), name) {
+				if j, dup := lookup[key]; dup && j != i {
+					lookup[key] = -1
+				} else {
+					lookup[key] = i
+				}
+			}
+		}
+		t.byMode[mode] = lookup
+	}
+	return t
+}
+
+// matchKeys lists the keys accepted for an input in a mode. Fuzzy keys are
+// normalized with fuzzyKey before the lookup.
+func matchKeys(mode MatchMode, name string) []string {
+	switch mode {
+	case MatchStrict:
+		return []string{name}
+	case MatchSnakeCamel:
+		keys := []string{name, snakeToCamel(name)}
+		if whole := joinLetters(name); whole != name {
+			keys = append(keys, whole, snakeToCamel(whole))
+		}
+		return keys
+	}
+	return []string{fuzzyKey(name)}
+}
+
+// joinLetters joins the runs of one-letter words that the catalog names
+// get from acronyms: user_i_d -> user_id, h_t_t_p_code -> http_code.
+func joinLetters(name string) string {
+	var words []string
+	run := ""
+	for _, w := range strings.Split(name, "_") {
+		if len([]rune(w)) == 1 {
+			run += w
+			continue
+		}
+		if run != "" {
+			words, run = append(words, run), ""
+		}
+		words = append(words, w)
+	}
+	if run != "" {
+		words = append(words, run)
+	}
+	return strings.Join(words, "_")
+}
+
+func fuzzyKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+func snakeToCamel(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			r := []rune(parts[i])
+			r[0] = unicode.ToUpper(r[0])
+			parts[i] = string(r)
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// bind returns the value of each input, nil when missing. Keys that match
+// no input are ignored; a key matching several inputs, or two keys
+// matching the same input, are a *RequestError.
+// Only args is allocated on success; the errors are built when found.
+func (t *paramTable) bind(params map[string]json.RawMessage, mode MatchMode) ([]json.RawMessage, error) {
+	args := make([]json.RawMessage, len(t.names))
+	var errs *RequestError
+	for k, v := range params {
+		i, ok := t.lookup(k, mode)
+		switch {
+		case !ok:
+			continue
+		case i < 0:
+			if errs == nil {
+				errs = &RequestError{}
+			}
+			errs.add(k, "ambiguous", "matches more than one param")
+		case args[i] != nil:
+			if errs == nil {
+				errs = &RequestError{}
+			}
+			first, second := t.otherKey(params, mode, i, k), k
+			if second < first {
+				first, second = second, first
+			}
+			errs.add(t.names[i], "ambiguous", "given twice, as \""+first+"\" and \""+second+"\"")
+		default:
+			args[i] = v
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	return args, nil
+}
+
+// lookup returns the input a key of params binds to in mode. Fuzzy keys
+// of up to 64 ASCII bytes are normalized on the stack: the map index with
+// string(buf) does not allocate.
+func (t *paramTable) lookup(key string, mode MatchMode) (int, bool) {
+	if mode != MatchFuzzy {
+		i, ok := t.byMode[mode][key]
+		return i, ok
+	}
+	var buf [64]byte
+	n := 0
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= 0x80 || n == len(buf) {
+			i, ok := t.byMode[mode][fuzzyKey(key)]
+			return i, ok
+		}
+		switch {
+		case c == '_':
+		case 'A' <= c && c <= 'Z':
+			buf[n], n = c+'a'-'A', n+1
+		default:
+			buf[n], n = c, n+1
+		}
+	}
+	i, ok := t.byMode[mode][string(buf[:n])]
+	return i, ok
+}
+
+// otherKey returns a key of params, other than key, bound to input i.
+func (t *paramTable) otherKey(params map[string]json.RawMessage, mode MatchMode, i int, key string) string {
+	for k := range params {
+		if j, ok := t.lookup(k, mode); ok && j == i && k != key {
+			return k
+		}
+	}
+	return ""
+}
+
+// flatten collects the struct fields given at the top level of params
+// (args[1:]) into an object for the only input, args[0]. It returns nil
+// when no field was given.
+func (t *paramTable) flatten(args []json.RawMessage) (json.RawMessage, error) {
+	var flat map[string]json.RawMessage
+	var given []string
+	for i := 1; i < len(args); i++ {
+		if args[i] == nil {
+			continue
+		}
+		if flat == nil {
+			flat = make(map[string]json.RawMessage)
+		}
+		flat[t.names[i]] = args[i]
+		given = append(given, t.names[i])
+	}
+	if flat != nil && args[0] != nil {
+		errs := &RequestError{}
+		errs.add(t.names[0], "conflict", "given both nested and as top-level fields ("+strings.Join(given, ", ")+")")
+		return nil, errs
+	}
+	if flat == nil {
+		return nil, nil
+	}
+	return json.Marshal(flat)
+}
+
+// Inputs with an encoding of their own; see decodeParam.
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// decodeParam decodes one value of params into v, leaving v untouched when
+// the value is missing. On top of encoding/json:
+//   - numbers are decoded from their text, so int64 inputs keep every digit,
+//     fractional or out of range values are rejected and interface{}
+//     values get a json.Number;
+//   - numeric inputs, *big.Int, *big.Float and *big.Rat included, also
+//     accept the number as a string: "9007199254740993";
+//   - time.Duration is "1m30s" or an integer of nanoseconds;
+//   - time.Time is an RFC 3339 string and []byte a base64 string.
+// Values that do not decode are added to errs under path.
+func decodeParam(errs *RequestError, path string, raw json.RawMessage, v interface{}) {
+	if raw == nil {
+		return
+	}
+	t := reflect.TypeOf(v).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var err error
+	if raw = normalizeParam(raw, t); holdsInterface(t) {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		err = dec.Decode(v)
+	} else {
+		err = json.Unmarshal(raw, v) // Typed numbers are parsed from their text as well
+	}
+	if err == nil {
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			path += "." + typeErr.Field
+		}
+		errs.add(path, "type", typeMessage(typeErr.Type, typeErr.Value))
+	case errors.As(err, &timeErr):
+		errs.add(path, "type", "must be "+jsonKind(timeType))
+	case t == bigIntType || t == bigFloatType || t == bigRatType:
+		errs.add(path, "type", "must be "+jsonKind(t))
+	default:
+		errs.add(path, "type", err.Error())
+	}
+}
+
+// normalizeParam rewrites the encodings accepted for t that encoding/json
+// does not decode by itself.
+func normalizeParam(raw json.RawMessage, t reflect.Type) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	quoted := raw[0] == '"'
+	switch {
+	case quoted && t == durationType:
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			if d, err := time.ParseDuration(s); err == nil {
+				return json.RawMessage(strconv.FormatInt(int64(d), 10))
+			}
+		}
+	case quoted && (isNumberKind(t.Kind()) || t == bigIntType):
+		if s := raw[1 : len(raw)-1]; isJSONNumber(s) { // A number has no escapes to undo
+			return s
+		}
+	case !quoted && (t == bigFloatType || t == bigRatType) && isJSONNumber(raw):
+		// Both only decode from strings
+		return json.RawMessage(strconv.Quote(string(raw)))
+	}
+	return raw
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isJSONNumber(b []byte) bool {
+	if len(b) == 0 || (b[0] != '-' && (b[0] < '0' || b[0] > '9')) {
+		return false
+	}
+	return json.Valid(b)
+}
+
+// interfaceTypes memoizes holdsInterface: reflect.Type -> bool.
+var interfaceTypes sync.Map
+
+// holdsInterface reports whether a value of t can hold an interface{}, the
+// only case decodeParam needs a json.Decoder for, to get json.Number.
+// Other inputs decode with json.Unmarshal, which allocates no buffer.
+func holdsInterface(t reflect.Type) bool {
+	if holds, ok := interfaceTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	holds := containsInterface(t, make(map[reflect.Type]bool))
+	interfaceTypes.Store(t, holds)
+	return holds
+}
+
+func containsInterface(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return containsInterface(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); (f.IsExported() || f.Anonymous) && containsInterface(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeMessage describes why value, as reported by encoding/json ("string",
+// "number 300"), is not a valid t.
+func typeMessage(t reflect.Type, value string) string {
+	if number, ok := strings.CutPrefix(value, "number "); ok && isNumberKind(t.Kind()) && t != durationType {
+		fractional := strings.ContainsAny(number, ".eE")
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return "must be a number within " + t.Kind().String() + " range, got " + number
+		default:
+			if !fractional {
+				return "must be an integer within " + t.Kind().String() + " range, got " + number
+			}
+		}
+	}
+	if value == "string" && (t == durationType || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8)) {
+		return "must be " + jsonKind(t) // A string, just not a valid one
+	}
+	return "must be " + jsonKind(t) + ", got " + value
+}
+
+// jsonKind names the JSON value expected for t.
+func jsonKind(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "a duration like \"1m30s\" or an integer of nanoseconds"
+	case timeType:
+		return "an RFC 3339 timestamp"
+	case bigIntType:
+		return "an integer"
+	case bigFloatType, bigRatType:
+		return "a number"
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "a base64 string"
+		}
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return t.String()
+}
+
+{{range .Handlers}}{{if .Inputs}}
+var params{{.FuncAlias}}_{{.FuncName}} = newParamTable({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}"{{$e.Name}}"{{end}}{{range .FlatFields}}, "{{.}}"{{end}})
+
+// request{{.FuncAlias}}_{{.FuncName}} holds the decoded inputs of {{.FuncName}}.
+type request{{.FuncAlias}}_{{.FuncName}} struct {
+	{{range .Inputs}}{{.Name}} {{.GoType}}
+	{{end}}
+}
+{{end}}{{end}}
+
+// --- Validation ---
+
+{{.Validators}}{{range .Handlers}}{{if .Validation}}
+// validate checks the inputs of {{.FuncName}} before the HTTP and gRPC calls.{{if .Flatten}}
+// path names {{(index .Inputs 0).Name}} in field errors: "" when its fields were
+// given at the top level.{{end}}
+func (in *request{{.FuncAlias}}_{{.FuncName}}) validate(errs *RequestError{{if .Flatten}}, path string{{end}}) {
+	{{.Validation}}
+}
+{{end}}{{end}}
+
 {{range .Handlers}}
-func handle{{.FuncAlias}}_{{.FuncName}}(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handle{{.FuncAlias}}_{{.FuncName}}(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -30,65 +606,57 @@ func handle{{.FuncAlias}}_{{.FuncName}}(w http.ResponseWriter, r *http.Request)
 	params := req.Params
 	
 	// 2. Call Implementation
-	{{if .Outputs}}resp, err := {{else}}{{end}}wrapper{{.FuncAlias}}_{{.FuncName}}(params)
+	{{if .Outputs}}resp, err{{else}}_, err{{end}} := wrapper{{.FuncAlias}}_{{.FuncName}}(params, h.options.matchModeFor("{{.Route}}"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	{{if .Outputs}}
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	{{if .Outputs}}
 	json.NewEncoder(w).Encode(resp)
 	{{else}}
 	w.WriteHeader(http.StatusOK)
 	{{end}}
 }
 
-func wrapper{{.FuncAlias}}_{{.FuncName}}(params map[string]interface{}) ({{if .Outputs}}interface{}, error{{else}}{{end}}) {
+func wrapper{{.FuncAlias}}_{{.FuncName}}(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: {{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}{{$e.Name}}({{$e.Type}}){{end}}
     
     {{$alias := .FuncAlias}}
-    {{range .Inputs}}
-    
-    // Determine Type string (Primitive vs Complex)
-    {{if or (eq .Type "string") (eq .Type "float64") (eq .Type "int") (eq .Type "bool")}}
-    var val_{{.Name}} {{.Type}}
-    {{else}}
-    var val_{{.Name}} {{$alias}}.{{.Type}}
+    {{if .Inputs}}
+    args, bindErr := params{{.FuncAlias}}_{{.FuncName}}.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
+    }
     {{end}}
-
-    // Fuzzy Match Logic
-    found_{{.Name}} := false
-    target_{{.Name}} := strings.ToLower(strings.ReplaceAll("{{.Name}}", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_{{.Name}} {
-            {{if eq .Type "string"}}
-            val_{{.Name}}, _ = v.(string)
-            {{else if eq .Type "float64"}}
-            val_{{.Name}}, _ = v.(float64)
-            {{else if eq .Type "int"}}
-             // JSON numbers are float64
-             if fVal, ok := v.(float64); ok {
-                 val_{{.Name}} = int(fVal)
-             }
-            {{else if eq .Type "bool"}}
-            val_{{.Name}}, _ = v.(bool)
-            {{else}}
-            // Complex Type: Convert map -> json -> struct
-            jsonBody, _ := json.Marshal(v)
-            json.Unmarshal(jsonBody, &val_{{.Name}})
-            {{end}}
-            found_{{.Name}} = true
-            break
-        }
+    {{if .Flatten}}
+    // Flattened: the fields of {{(index .Inputs 0).Name}} may also be given at the top level of params
+    flat, flatErr := params{{.FuncAlias}}_{{.FuncName}}.flatten(args)
+    if flatErr != nil {
+        return nil, flatErr
     }
-    
-    if !found_{{.Name}} {
-       // Optional: Log or Error if required param missing?
+    path := "{{(index .Inputs 0).Name}}"
+    if flat != nil {
+        args[0], path = flat, "" // Fields are reported without the param name
+    }
+    {{end}}
+    {{if .Inputs}}
+    // Decode
+    var in request{{.FuncAlias}}_{{.FuncName}}
+    errs := &RequestError{}
+    {{$flatten := .Flatten}}{{range $i, $in := .Inputs}}decodeParam(errs, {{if $flatten}}path{{else}}"{{.Name}}"{{end}}, args[{{$i}}], &in.{{.Name}})
+    {{end}}
+    if len(errs.Fields) > 0 {
+        return nil, errs
+    }
+    {{end}}
+    {{if .Validation}}
+    // Validate
+    in.validate(errs{{if .Flatten}}, path{{end}})
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     {{end}}
 
@@ -96,25 +664,25 @@ func wrapper{{.FuncAlias}}_{{.FuncName}}(params map[string]interface{}) ({{if .O
     {{if .HasError}}
         {{if eq .NumReturns 2}}
             // Expected: (val, error)
-            ret0, err := {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}val_{{$e.Name}}{{end}})
+            ret0, err := {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}in.{{$e.Name}}{{end}})
             if err != nil {
                 return nil, err
             }
             return ret0, nil
         {{else}}
              // Expected: (error) ONLY? or (val1, val2, error) - simplifying to (error)
-             err := {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}val_{{$e.Name}}{{end}})
+             err := {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}in.{{$e.Name}}{{end}})
              return nil, err
         {{end}}
     {{else}}
         // No error returned
         {{if gt .NumReturns 0}}
             // Expected: (val)
-            ret0 := {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}val_{{$e.Name}}{{end}})
+            ret0 := {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}in.{{$e.Name}}{{end}})
             return ret0, nil
         {{else}}
             // Expected: void
-            {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}val_{{$e.Name}}{{end}})
+            {{$alias}}.{{.FuncName}}({{range $i, $e := .Inputs}}{{if gt $i 0}}, {{end}}in.{{$e.Name}}{{end}})
             return nil, nil
         {{end}}
     {{end}}
@@ -174,16 +742,21 @@ type {{$struct.Name}} struct {
 
 {{range .Methods}}
 func (c *{{$struct.Name}}) {{.Method}}(req GenericRequest) (interface{}, error) {
-	return c.transport.Call("{{.Namespace}}.{{.Method}}", req)
+	return c.transport.Call("{{.Endpoint}}", req)
 }
 {{end}}
 {{end}}
 
 func NewClient(baseURL string) *Client {
-	t := &httpTransport{
+	return NewClientWithTransport(&httpTransport{
 		BaseURL: baseURL,
 		Client:  &http.Client{},
-	}
+	})
+}
+
+// NewClientWithTransport builds the client tree on top of any Transport,
+// e.g. NewInProcessTransport() or NewMockTransport() in tests.
+func NewClientWithTransport(t Transport) *Client {
 	c := &Client{transport: t}
 	
 	// Dynamic Init
@@ -193,8 +766,169 @@ func NewClient(baseURL string) *Client {
 }
 `
 
-const TypesTemplate = `package generated
+const TransportTemplate = `package generated
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Method names as used by Transport.Call.
+const (
+{{range .Methods}}	Method{{.Name}} = "{{.Route}}"
+{{end}})
+
+// --- In-Process Transport ---
+
+// InProcessTransport calls the server wrappers directly, without HTTP.
+// Params and results go through JSON so callers see the same shapes
+// (float64 numbers, map[string]interface{} structs) as with NewClient, and
+// params are bound and decoded exactly as by the HTTP server. Of the
+// Options, only the match modes apply.
+type InProcessTransport struct {
+	options serverOptions
+}
+
+func NewInProcessTransport(opts ...Option) *InProcessTransport {
+	t := &InProcessTransport{}
+	for _, opt := range opts {
+		opt(&t.options)
+	}
+	return t
+}
+
+// NewInProcessClient returns a Client backed by an InProcessTransport.
+func NewInProcessClient() *Client {
+	return NewClientWithTransport(NewInProcessTransport())
+}
+
+var inProcessWrappers = map[string]func(params map[string]json.RawMessage, mode MatchMode) (interface{}, error){
+{{range .Methods}}	Method{{.Name}}: {{.Wrapper}},
+{{end}}}
+
+func (t *InProcessTransport) Call(method string, req GenericRequest) (interface{}, error) {
+	wrapper, ok := inProcessWrappers[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown method: %s", method)
+	}
+
+	var params map[string]json.RawMessage
+	if err := roundTripJSON(req.Params, &params); err != nil {
+		return nil, fmt.Errorf("invalid params: %v", err)
+	}
+
+	resp, err := wrapper(params, t.options.matchModeFor(method))
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	if err := roundTripJSON(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func roundTripJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// --- Mock Transport ---
+
+// MockHandler answers a call made through a MockTransport.
+type MockHandler func(params map[string]interface{}) (interface{}, error)
+
+// MockCall records one call made through a MockTransport.
+type MockCall struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// MockTransport is a programmable Transport for unit tests. Methods without
+// a stub fail with an error; every call is recorded, stubbed or not.
+type MockTransport struct {
+	mu    sync.Mutex
+	stubs map[string]MockHandler
+	calls []MockCall
+}
 
+func NewMockTransport() *MockTransport {
+	return &MockTransport{stubs: make(map[string]MockHandler)}
+}
+
+// On stubs a method by name (see the Method* constants).
+func (m *MockTransport) On(method string, handler MockHandler) *MockTransport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs[method] = handler
+	return m
+}
+
+// Return stubs a method with a fixed result and error.
+func (m *MockTransport) Return(method string, result interface{}, err error) *MockTransport {
+	return m.On(method, func(map[string]interface{}) (interface{}, error) {
+		return result, err
+	})
+}
+
+func (m *MockTransport) Call(method string, req GenericRequest) (interface{}, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, MockCall{Method: method, Params: req.Params})
+	handler, ok := m.stubs[method]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("mock: no stub for method %s", method)
+	}
+	return handler(req.Params)
+}
+
+// Calls returns every recorded call in order.
+func (m *MockTransport) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls to one method.
+func (m *MockTransport) CallsTo(method string) []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []MockCall
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset drops all stubs and recorded calls.
+func (m *MockTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs = make(map[string]MockHandler)
+	m.calls = nil
+}
+{{range .Methods}}
+func (m *MockTransport) On{{.Name}}(handler MockHandler) *MockTransport {
+	return m.On(Method{{.Name}}, handler)
+}
+{{end}}`
+
+const TypesTemplate = `package generated
+{{if .Imports}}
+import (
+{{- range $path, $alias := .Imports}}
+	{{if $alias}}{{$alias}} {{end}}"{{$path}}"
+{{- end}}
+)
+{{end}}
 // --- Shared Types ---
 
 type GenericRequest struct {
@@ -209,3 +943,841 @@ type {{.Name}} struct {
 }
 {{end}}
 `
+
+const ProtoTemplate = `syntax = "proto3";
+
+package {{.Package}};
+{{range .Enums}}
+enum {{.Name}} {
+{{- range .Values}}
+  {{.Name}} = {{.Number}};
+{{- end}}
+}
+{{end}}
+{{- range .Messages}}
+message {{.Name}} {
+{{- range .Fields}}
+  {{.ProtoType}} {{.Name}} = {{.Number}};{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+{{end}}
+{{- range .Services}}
+// {{.Namespace}}
+service {{.Name}} {
+{{- range .Methods}}
+{{- range .Comments}}
+  // {{.}}
+{{- end}}
+  rpc {{.Name}}({{.Request.Name}}) returns ({{.Response.Name}});
+{{- end}}
+{{- range .Skipped}}
+  // Skipped {{.}}
+{{- end}}
+}
+{{end}}`
+
+const GRPCTemplate = `//go:build nexus_grpc
+
+package generated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+    
+	{{range $path, $alias := .Imports}}
+	{{$alias}} "{{$path}}"
+	{{end}}
+)
+
+// NewGRPCServer returns a gRPC server exposing every service described in
+// nexus.proto. Messages are encoded by the generated functions below, so
+// no protoc output is required.
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(GRPCServerOptions(), opts...)...)
+	RegisterGRPCServices(s)
+	return s
+}
+
+// GRPCServerOptions returns the options a server needs to serve
+// RegisterGRPCServices: the generated codec and, with WithMaxBodyBytes, the
+// largest message it receives (0 for no limit, as on HTTP). Without it gRPC
+// keeps its default of 4 MiB. With WithMetrics, calls are recorded like
+// HTTP requests, labeled by the namespace and method of the catalog.
+func GRPCServerOptions(opts ...Option) []grpc.ServerOption {
+	var o serverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	serverOpts := []grpc.ServerOption{grpc.ForceServerCodec(grpcCodec{})}
+	if o.maxBodySet {
+		limit := int64(math.MaxInt32)
+		if o.maxBody > 0 {
+			limit = min(o.maxBody, limit)
+		}
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(int(limit)))
+	}
+	if o.metrics != nil {
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(o.grpcMetrics()))
+	}
+	return serverOpts
+}
+
+// grpcRoutes maps full gRPC method names to the namespace and method of
+// the catalog.
+var grpcRoutes = map[string][2]string{
+	{{range $svc := .Services}}{{range .Methods}}"/{{$.Package}}.{{$svc.Name}}/{{.Name}}": {"{{$svc.Namespace}}", "{{.Name}}"},
+	{{end}}{{end}}
+}
+
+// grpcMetrics records every call in o.metrics with the status code HTTP
+// would answer, so both transports share the same series.
+func (o *serverOptions) grpcMetrics() grpc.UnaryServerInterceptor {
+	for _, route := range grpcRoutes {
+		if o.serves(route[0]) {
+			o.metrics.Register(route[0], route[1])
+		}
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		route, ok := grpcRoutes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		o.metrics.Observe(route[0], route[1], grpcHTTPStatus(status.Code(err)), time.Since(start))
+		return resp, err
+	}
+}
+
+// grpcHTTPStatus maps a gRPC code to the HTTP status of the same meaning.
+func grpcHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// RegisterGRPCServices registers the services of nexus.proto on s. With
+// WithNamespaceFilter it only registers the namespaces the HTTP server
+// serves; other Options are ignored.
+func RegisterGRPCServices(s *grpc.Server, opts ...Option) {
+	var o serverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	{{range .Services}}{{if .Methods}}
+	if o.serves("{{.Namespace}}") {
+		s.RegisterService(&grpcDesc{{.Name}}, struct{}{})
+	}
+	{{end}}{{end}}
+}
+
+{{range $svc := .Services}}{{if .Methods}}
+var grpcDesc{{$svc.Name}} = grpc.ServiceDesc{
+	ServiceName: "{{$.Package}}.{{$svc.Name}}",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{{range .Methods}}
+		{
+			MethodName: "{{.Name}}",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/{{$.Package}}.{{$svc.Name}}/{{.Name}}", grpc{{.Alias}}_{{.Name}})
+			},
+		},
+		{{end}}
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nexus.proto",
+}
+{{end}}{{end}}
+
+// --- Adapters ---
+{{range .Services}}{{range .Methods}}
+func grpc{{.Alias}}_{{.Name}}(in []byte) ([]byte, error) {
+	var p {{.Request.GoType}}
+	if err := pbDecode{{.Request.Name}}(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	{{if .Validate}}
+	// Validate, with the checks of the HTTP handler
+	req := request{{.Alias}}_{{.Name}}{ {{range .Request.Fields}}{{.Name}}: p.{{.GoName}}, {{end}} }
+	errs := &RequestError{}
+	req.validate(errs{{if .Flatten}}, "{{(index .Request.Fields 0).Name}}"{{end}})
+	if len(errs.Fields) > 0 {
+		return nil, grpcInvalidArgument(errs)
+	}
+	{{end}}
+
+	{{if .Lhs}}{{.Lhs}} := {{end}}{{.Alias}}.{{.Name}}({{.Args}})
+	{{if .HasError}}
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	{{end}}
+
+	var r {{.Response.GoType}}
+	{{range .Response.Fields}}
+	r.{{.GoName}} = {{.Local}}
+	{{end}}
+	return pbEncode{{.Response.Name}}(nil, &r), nil
+}
+{{end}}{{end}}
+
+// --- Messages ---
+{{range .Messages}}{{if .Synthetic}}
+type {{.GoType}} struct {
+	{{range .Fields}}
+	{{.GoName}} {{.GoType}}
+	{{end}}
+}
+{{end}}{{end}}
+
+{{range .Enums}}
+var pbEnum{{.Name}} = []{{.GoType}}{ {{range .Values}}{{if .GoName}}{{.GoName}}, {{end}}{{end}} }
+
+func pbEnumIndex{{.Name}}(v {{.GoType}}) uint64 {
+	for i, c := range pbEnum{{.Name}} {
+		if c == v {
+			return uint64(i + 1)
+		}
+	}
+	return 0
+}
+
+func pbEnumValue{{.Name}}(x uint64) {{.GoType}} {
+	if x == 0 || x > uint64(len(pbEnum{{.Name}})) {
+		var zero {{.GoType}}
+		return zero
+	}
+	return pbEnum{{.Name}}[x-1]
+}
+{{end}}
+
+{{range .Messages}}
+func pbEncode{{.Name}}(b []byte, v *{{.GoType}}) []byte {
+	{{range .Fields}}
+	{{.Encode}}
+	{{end}}
+	return b
+}
+
+func pbDecode{{.Name}}(b []byte, v *{{.GoType}}) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		{{range .Fields}}
+		case {{.Number}}:
+			{{.Decode}}
+		{{end}}
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+{{end}}
+
+// grpcMessageRoundTrips decodes a message of nexus.proto into its Go type
+// and encodes it back, by proto name. Tests compare them with the .proto.
+var grpcMessageRoundTrips = map[string]func(in []byte) ([]byte, error){
+	{{range .Messages}}"{{.Name}}": func(in []byte) ([]byte, error) {
+		var v {{.GoType}}
+		if err := pbDecode{{.Name}}(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncode{{.Name}}(nil, &v), nil
+	},
+	{{end}}
+}
+
+// --- Runtime ---
+
+// grpcRawMessage carries already-encoded protobuf bytes through grpc-go.
+type grpcRawMessage []byte
+
+type grpcCodec struct{}
+
+func (grpcCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(*grpcRawMessage)
+	if !ok {
+		return nil, errors.New("nexus grpc: unexpected message type")
+	}
+	return *msg, nil
+}
+
+func (grpcCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*grpcRawMessage)
+	if !ok {
+		return errors.New("nexus grpc: unexpected message type")
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+func (grpcCodec) Name() string {
+	return "proto"
+}
+
+func grpcUnary(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor, fullMethod string, call func([]byte) ([]byte, error)) (interface{}, error) {
+	var in grpcRawMessage
+	if err := dec(&in); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		out, err := call(*req.(*grpcRawMessage))
+		if err != nil {
+			return nil, err
+		}
+		msg := grpcRawMessage(out)
+		return &msg, nil
+	}
+	if interceptor == nil {
+		return handler(ctx, &in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+	return interceptor(ctx, &in, info, handler)
+}
+
+// grpcInvalidArgument is the gRPC form of the 400 answered to a
+// *RequestError: InvalidArgument with a BadRequest detail listing the
+// fields.
+func grpcInvalidArgument(errs *RequestError) error {
+	detail := &errdetails.BadRequest{}
+	for _, f := range errs.Fields {
+		detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+			Reason:      f.Rule,
+		})
+	}
+	st := status.New(codes.InvalidArgument, errs.Error())
+	if withDetail, err := st.WithDetails(detail); err == nil {
+		st = withDetail
+	}
+	return st.Err()
+}
+
+var errWireType = errors.New("nexus grpc: unexpected wire type")
+
+func pbAppendString(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func pbAppendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func pbAppendBool(b []byte, num protowire.Number, v bool) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, protowire.EncodeBool(v))
+}
+
+func pbAppendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func pbAppendDouble(b []byte, num protowire.Number, v float64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func pbAppendFloat(b []byte, num protowire.Number, v float32) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, math.Float32bits(v))
+}
+
+func pbAppendJSON(b []byte, num protowire.Number, v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return pbAppendBytes(b, num, data)
+}
+
+func pbReadString(b []byte, typ protowire.Type) (string, int, error) {
+	if typ != protowire.BytesType {
+		return "", 0, errWireType
+	}
+	v, n := protowire.ConsumeString(b)
+	if n < 0 {
+		return "", 0, protowire.ParseError(n)
+	}
+	return v, n, nil
+}
+
+func pbReadBytes(b []byte, typ protowire.Type) ([]byte, int, error) {
+	if typ != protowire.BytesType {
+		return nil, 0, errWireType
+	}
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return nil, 0, protowire.ParseError(n)
+	}
+	return append([]byte(nil), v...), n, nil
+}
+
+func pbReadVarint(b []byte, typ protowire.Type) (uint64, int, error) {
+	if typ != protowire.VarintType {
+		return 0, 0, errWireType
+	}
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, 0, protowire.ParseError(n)
+	}
+	return v, n, nil
+}
+
+func pbReadBool(b []byte, typ protowire.Type) (bool, int, error) {
+	v, n, err := pbReadVarint(b, typ)
+	return protowire.DecodeBool(v), n, err
+}
+
+func pbReadDouble(b []byte, typ protowire.Type) (float64, int, error) {
+	if typ != protowire.Fixed64Type {
+		return 0, 0, errWireType
+	}
+	v, n := protowire.ConsumeFixed64(b)
+	if n < 0 {
+		return 0, 0, protowire.ParseError(n)
+	}
+	return math.Float64frombits(v), n, nil
+}
+
+func pbReadFloat(b []byte, typ protowire.Type) (float32, int, error) {
+	if typ != protowire.Fixed32Type {
+		return 0, 0, errWireType
+	}
+	v, n := protowire.ConsumeFixed32(b)
+	if n < 0 {
+		return 0, 0, protowire.ParseError(n)
+	}
+	return math.Float32frombits(v), n, nil
+}
+
+// Times are RFC 3339 strings; the zero time is the empty string.
+func pbAppendTime(b []byte, num protowire.Number, v time.Time) []byte {
+	if v.IsZero() {
+		return pbAppendString(b, num, "")
+	}
+	return pbAppendString(b, num, v.Format(time.RFC3339Nano))
+}
+
+func pbReadTime(b []byte, typ protowire.Type) (time.Time, int, error) {
+	s, n, err := pbReadString(b, typ)
+	if err != nil || s == "" {
+		return time.Time{}, n, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, n, err
+}
+
+// pbDecodeFields calls field for each field of a message, which reads it
+// and returns its length. Map entries are decoded with it.
+func pbDecodeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// pbSkip returns the length of a field the message does not know.
+func pbSkip(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	n := protowire.ConsumeFieldValue(num, typ, b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return n, nil
+}
+
+func pbReadJSON(b []byte, typ protowire.Type, v interface{}) (int, error) {
+	data, n, err := pbReadBytes(b, typ)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return n, nil
+	}
+	return n, json.Unmarshal(data, v)
+}
+`
+
+const TSPackageTemplate = `{
+  "name": "{{.PackageName}}",
+  "version": "0.0.0",
+  "private": true,
+  "main": "dist/index.js",
+  "types": "dist/index.d.ts",
+  "scripts": {
+    "build": "tsc -p ."
+  }
+}
+`
+
+const TSConfigTemplate = `{
+  "compilerOptions": {
+    "target": "ES2020",
+    "module": "ES2020",
+    "moduleResolution": "node",
+    "lib": ["ES2020", "DOM"],
+    "declaration": true,
+    "strict": true,
+    "outDir": "dist"
+  },
+  "include": ["src"]
+}
+`
+
+const TSTypesTemplate = `// --- Shared Types ---
+{{range .Aliases}}
+export type {{.Name}} = {{.Type}};
+{{end}}
+{{- range .Interfaces}}
+export interface {{.Name}} {
+{{- range .Fields}}
+  {{.Name}}{{if .Optional}}?{{end}}: {{.Type}};
+{{- end}}
+}
+{{end}}`
+
+const TSClientTemplate = `{{if .TypeNames}}import type {
+{{- range .TypeNames}}
+  {{.}},
+{{- end}}
+} from "./types";
+{{end}}
+export * from "./types";
+
+export interface Transport {
+  call<R>(method: string, params: object): Promise<R>;
+}
+
+export interface ClientOptions {
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+/** NexusError mirrors the server error envelope: {"error": "..."} */
+export class NexusError extends Error {
+  readonly status: number;
+  readonly body: string;
+
+  constructor(status: number, message: string, body: string) {
+    super(message);
+    this.name = "NexusError";
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export class HttpTransport implements Transport {
+  private readonly baseURL: string;
+  private readonly options: ClientOptions;
+
+  constructor(baseURL: string, options: ClientOptions = {}) {
+    this.baseURL = baseURL.replace(/\/+$/, "");
+    this.options = options;
+  }
+
+  async call<R>(method: string, params: object): Promise<R> {
+    const doFetch = this.options.fetch ?? fetch;
+    const resp = await doFetch(this.baseURL + "/" + method, {
+      method: "POST",
+      headers: { "Content-Type": "application/json", ...this.options.headers },
+      body: JSON.stringify({ params }),
+    });
+    const text = await resp.text();
+    if (!resp.ok) {
+      let message = resp.status + " " + resp.statusText;
+      try {
+        const envelope = JSON.parse(text);
+        if (envelope && typeof envelope.error === "string") {
+          message = envelope.error;
+        }
+      } catch {
+        if (text.trim() !== "") {
+          message = text.trim();
+        }
+      }
+      throw new NexusError(resp.status, message, text);
+    }
+    return (text === "" ? null : JSON.parse(text)) as R;
+  }
+}
+{{range .Clients}}
+export class {{.Name}} {
+{{- range .Fields}}
+  readonly {{.Name}}: {{.Type}};
+{{- end}}
+{{- if eq .Name "Client"}}
+
+  constructor(target: string | Transport, options: ClientOptions = {}) {
+    const transport = typeof target === "string" ? new HttpTransport(target, options) : target;
+{{- else}}
+  private readonly transport: Transport;
+
+  constructor(transport: Transport) {
+    this.transport = transport;
+{{- end}}
+{{- range .Fields}}
+    this.{{.Name}} = new {{.Type}}(transport);
+{{- end}}
+  }
+{{- range .Methods}}
+{{if .Comments}}
+  /**
+{{- range .Comments}}
+   * {{.}}
+{{- end}}
+   */{{end}}
+  {{.Name}}(params: {{.ParamsType}}): Promise<{{.ResultType}}> {
+    return this.transport.call<{{.ResultType}}>("{{.Route}}", params);
+  }
+{{- end}}
+}
+{{end}}`
+
+const PyProjectTemplate = `[build-system]
+requires = ["setuptools>=61"]
+build-backend = "setuptools.build_meta"
+
+[project]
+name = "nexus-sdk"
+version = "0.0.0"
+requires-python = ">=3.8"
+dependencies = []
+`
+
+const PyInitTemplate = `from .client import Client, HttpTransport, Transport
+from .errors import (
+    NexusBadRequestError,
+    NexusConnectionError,
+    NexusError,
+    NexusHTTPError,
+    NexusServerError,
+)
+from .models import *  # noqa: F401,F403
+`
+
+const PyErrorsTemplate = `import json
+
+
+class NexusError(Exception):
+    """Base class for every error raised by the SDK."""
+
+
+class NexusConnectionError(NexusError):
+    """The Nexus server could not be reached."""
+
+
+class NexusHTTPError(NexusError):
+    """The Nexus server answered with a non-2xx status."""
+
+    def __init__(self, status: int, message: str, body: str = "") -> None:
+        super().__init__("%d: %s" % (status, message))
+        self.status = status
+        self.message = message
+        self.body = body
+
+
+class NexusBadRequestError(NexusHTTPError):
+    """400: the request envelope or its params were rejected."""
+
+
+class NexusServerError(NexusHTTPError):
+    """500: the library returned an error, sent as {"error": "..."}."""
+
+
+def error_from_response(status: int, body: str) -> NexusHTTPError:
+    message = body.strip()
+    try:
+        envelope = json.loads(body)
+        if isinstance(envelope, dict) and isinstance(envelope.get("error"), str):
+            message = envelope["error"]
+    except ValueError:
+        pass
+    if status == 400:
+        return NexusBadRequestError(status, message, body)
+    if status >= 500:
+        return NexusServerError(status, message, body)
+    return NexusHTTPError(status, message, body)
+`
+
+const PyModelsTemplate = `from __future__ import annotations
+
+from dataclasses import dataclass, field
+from typing import Any, Callable, Dict, List, Optional, TypeVar
+
+T = TypeVar("T")
+R = TypeVar("R")
+
+
+def _optional(value: Optional[T], fn: Callable[[T], R]) -> Optional[R]:
+    return None if value is None else fn(value)
+{{range .Enums}}
+
+class {{.Name}}:
+{{- range .Values}}
+    {{.Name}} = {{.Value}}
+{{- else}}
+    pass
+{{- end}}
+{{end}}
+{{- range .Classes}}
+
+@dataclass
+class {{.Name}}:
+{{- range .Fields}}
+    {{.Attr}}: {{.Annotation}} = {{.Default}}
+{{- end}}
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> {{.Name}}:
+        return cls(
+{{- range .Fields}}
+            {{.Attr}}={{.Decode}},
+{{- end}}
+        )
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+{{- range .Fields}}
+            "{{.JSONName}}": {{.Encode}},
+{{- end}}
+        }
+{{end}}`
+
+const PyClientTemplate = `from __future__ import annotations
+
+import json
+import urllib.error
+import urllib.request
+from typing import Any, Dict, List, Optional
+
+try:
+    from typing import Protocol
+except ImportError:  # Python < 3.8
+    Protocol = object  # type: ignore
+
+from .errors import NexusConnectionError, error_from_response
+from .models import _optional
+{{- if .Exported}}
+from .models import (
+{{- range .Exported}}
+    {{.}},
+{{- end}}
+)
+{{- end}}
+
+
+class Transport(Protocol):
+    def call(self, method: str, params: Dict[str, Any]) -> Any:
+        ...
+
+
+class HttpTransport:
+    """POSTs {"params": ...} to <base_url>/<namespace>.<Method>."""
+
+    def __init__(self, base_url: str, timeout: float = 30.0, headers: Optional[Dict[str, str]] = None) -> None:
+        self.base_url = base_url.rstrip("/")
+        self.timeout = timeout
+        self.headers = dict(headers or {})
+
+    def call(self, method: str, params: Dict[str, Any]) -> Any:
+        body = json.dumps({"params": params}).encode("utf-8")
+        headers = {"Content-Type": "application/json"}
+        headers.update(self.headers)
+        request = urllib.request.Request(self.base_url + "/" + method, data=body, headers=headers, method="POST")
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                text = response.read().decode("utf-8")
+        except urllib.error.HTTPError as e:
+            raise error_from_response(e.code, e.read().decode("utf-8", "replace")) from None
+        except urllib.error.URLError as e:
+            raise NexusConnectionError(str(e.reason)) from e
+        return json.loads(text) if text.strip() else None
+{{range .Clients}}
+
+class {{.Name}}:
+{{- if eq .Name "Client"}}
+    def __init__(
+        self,
+        base_url: str = "http://localhost:8080",
+        *,
+        timeout: float = 30.0,
+        headers: Optional[Dict[str, str]] = None,
+        transport: Optional[Transport] = None,
+    ) -> None:
+        if transport is None:
+            transport = HttpTransport(base_url, timeout=timeout, headers=headers)
+{{- else}}
+    def __init__(self, transport: Transport) -> None:
+{{- end}}
+        self._transport = transport
+{{- range .Children}}
+        self.{{.Attr}} = {{.Type}}(transport)
+{{- end}}
+{{- range .Methods}}
+
+    def {{.Name}}(self{{range .Params}}, {{.Attr}}: {{.Annotation}}{{end}}) -> {{.ResultType}}:
+{{- if .Comments}}
+        """{{range $i, $c := .Comments}}{{if $i}}
+        {{end}}{{$c}}{{end}}"""
+{{- end}}
+        result = self._transport.call("{{.Route}}", {
+{{- range .Params}}
+            "{{.JSONName}}": {{.Encode}},
+{{- end}}
+        })
+        return {{.Decode}}
+{{- end}}
+{{end}}`
@@ -0,0 +1,69 @@
+// Package accounts is the library the generator tests build a server for.
+package accounts
+
+import (
+	"errors"
+	"time"
+)
+
+// Cents is an amount of money in hundredths of the currency unit.
+type Cents int64
+
+type TransferRequest struct {
+	SourceAccount string  `json:"source_account" validate:"required,min=4"`
+	Amount        float64 `json:"amount" validate:"gt=0"`
+	Currency      string  `json:"currency" validate:"oneof=USD EUR"`
+	Fee           Cents   `json:"fee" validate:"gte=0"`
+}
+
+type TransferResponse struct {
+	TransactionID string `json:"transaction_id"`
+	Status        string `json:"status"`
+}
+
+// ComplexTransfer performs a transfer using a struct input/output.
+func ComplexTransfer(req TransferRequest) (TransferResponse, error) {
+	if req.SourceAccount == "" {
+		return TransferResponse{}, errors.New("validation did not run")
+	}
+	return TransferResponse{TransactionID: "TX-" + req.SourceAccount, Status: "COMPLETED"}, nil
+}
+
+// Deposit adds an amount to an account and returns the new balance.
+//
+//nexus:validate account required
+//nexus:validate amount gt=0,lte=10000
+func Deposit(account string, amount float64) (float64, error) {
+	if account == "" {
+		return 0, errors.New("validation did not run")
+	}
+	return 100 + amount, nil
+}
+
+// Status is the state of an account.
+type Status string
+
+const (
+	StatusOK        Status = "OK"
+	StatusHTTPError Status = "HTTP_ERROR"
+	StatusClosed    Status = "CLOSED"
+)
+
+type Statement struct {
+	Account  string                     `json:"account"`
+	Status   Status                     `json:"status"`
+	Opened   time.Time                  `json:"opened"`
+	Hold     time.Duration              `json:"hold"`
+	Entries  []Cents                    `json:"entries"`
+	Flags    []bool                     `json:"flags"`
+	Statuses []Status                   `json:"statuses"`
+	Dates    []time.Time                `json:"dates"`
+	Totals   map[string]int64           `json:"totals"`
+	Limits   map[int32]Cents            `json:"limits"`
+	Payments map[string]TransferRequest `json:"payments"`
+}
+
+// GetStatement returns the statement of an account since a time.
+func GetStatement(account string, since time.Time, window time.Duration, ids []int64) (Statement, error) {
+	return Statement{Account: account, Status: StatusOK, Opened: since, Hold: window, Entries: []Cents{Cents(len(ids))}}, nil
+}
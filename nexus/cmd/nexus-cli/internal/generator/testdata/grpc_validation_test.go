@@ -0,0 +1,160 @@
+//go:build nexus_grpc
+
+package generated
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/generator/testdata/bank/accounts"
+	"github.com/japablazatww/nexus/nexus/metrics"
+)
+
+// TestGRPCValidation sends invalid requests to the generated gRPC server of
+// testdata/bank: they are answered InvalidArgument with the same field
+// errors the HTTP handler answers with a 400, and never reach the library.
+func TestGRPCValidation(t *testing.T) {
+	conn := dialBank(t)
+
+	tests := []struct {
+		name   string
+		method string
+		in     []byte
+		route  string
+		body   string
+		want   []FieldError
+	}{
+		{
+			name:   "struct",
+			method: "/nexus.BankAccountsService/ComplexTransfer",
+			in: pbEncodeBankAccountsComplexTransferParams(nil, &pbBankAccountsComplexTransferParams{
+				Req: accounts.TransferRequest{SourceAccount: "ab", Amount: -5, Currency: "GBP", Fee: -1},
+			}),
+			route: "/bank.accounts.ComplexTransfer",
+			body:  `{"params": {"req": {"source_account": "ab", "amount": -5, "currency": "GBP", "fee": -1}}}`,
+			want: []FieldError{
+				{Field: "req.source_account", Rule: "min", Message: "length must be at least 4"},
+				{Field: "req.amount", Rule: "gt", Message: "must be greater than 0"},
+				{Field: "req.currency", Rule: "oneof", Message: "must be one of USD EUR"},
+				{Field: "req.fee", Rule: "gte", Message: "must be at least 0"},
+			},
+		},
+		{
+			name:   "params",
+			method: "/nexus.BankAccountsService/Deposit",
+			in:     pbEncodeBankAccountsDepositParams(nil, &pbBankAccountsDepositParams{Amount: 20000}),
+			route:  "/bank.accounts.Deposit",
+			body:   `{"params": {"account": "", "amount": 20000}}`,
+			want: []FieldError{
+				{Field: "account", Rule: "required", Message: "is required"},
+				{Field: "amount", Rule: "lte", Message: "must be at most 10000"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, out := grpcRawMessage(tt.in), grpcRawMessage(nil)
+			err := conn.Invoke(context.Background(), tt.method, &in, &out)
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("got %v, want InvalidArgument", err)
+			}
+			var got []FieldError
+			for _, detail := range st.Details() {
+				if br, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range br.FieldViolations {
+						got = append(got, FieldError{Field: v.Field, Rule: v.Reason, Message: v.Description})
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("gRPC field violations\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+
+			mux := http.NewServeMux()
+			RegisterHandlers(mux)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("POST", tt.route, bytes.NewBufferString(tt.body)))
+			var resp struct{ Fields []FieldError }
+			json.NewDecoder(rec.Body).Decode(&resp)
+			if rec.Code != http.StatusBadRequest || !reflect.DeepEqual(resp.Fields, tt.want) {
+				t.Errorf("HTTP answered %d %+v, want 400 %+v", rec.Code, resp.Fields, tt.want)
+			}
+		})
+	}
+
+	// A valid request still reaches the library
+	in := grpcRawMessage(pbEncodeBankAccountsDepositParams(nil, &pbBankAccountsDepositParams{Account: "ES01", Amount: 50}))
+	var out grpcRawMessage
+	if err := conn.Invoke(context.Background(), "/nexus.BankAccountsService/Deposit", &in, &out); err != nil {
+		t.Fatal(err)
+	}
+	var result pbBankAccountsDepositResult
+	if err := pbDecodeBankAccountsDepositResult(out, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Result0 != 150 {
+		t.Errorf("Deposit returned %v, want 150", result.Result0)
+	}
+}
+
+// TestGRPCMetrics checks that gRPC calls are recorded in the series of the
+// HTTP routes, with the status HTTP would answer.
+func TestGRPCMetrics(t *testing.T) {
+	recorder := metrics.New()
+	conn := dialBank(t, WithMetrics(recorder))
+
+	for _, params := range []pbBankAccountsDepositParams{{Account: "ES01", Amount: 50}, {Amount: 50}} {
+		in, out := grpcRawMessage(pbEncodeBankAccountsDepositParams(nil, &params)), grpcRawMessage(nil)
+		conn.Invoke(context.Background(), "/nexus.BankAccountsService/Deposit", &in, &out)
+	}
+
+	var b strings.Builder
+	recorder.WriteTo(&b)
+	for _, want := range []string{
+		`nexus_requests_total{namespace="bank.accounts",method="Deposit"} 2`,
+		`nexus_request_errors_total{namespace="bank.accounts",method="Deposit",status="400"} 1`,
+		`nexus_requests_total{namespace="bank.accounts",method="ComplexTransfer"} 0`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("metrics do not contain %s:\n%s", want, b.String())
+		}
+	}
+}
+
+// dialBank serves the generated services on an in-memory listener and
+// returns a client that speaks the generated codec.
+func dialBank(t *testing.T, opts ...Option) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(GRPCServerOptions(opts...)...)
+	RegisterGRPCServices(s, opts...)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bank",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(grpcCodec{})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
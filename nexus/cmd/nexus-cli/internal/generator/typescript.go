@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+type tsField struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
+type tsInterface struct {
+	Name   string
+	Fields []tsField
+}
+
+type tsAlias struct {
+	Name string
+	Type string
+}
+
+type tsMethod struct {
+	Name       string
+	Route      string
+	ParamsType string
+	ResultType string
+	Comments   []string
+}
+
+type tsClientField struct {
+	Name string
+	Type string
+}
+
+type tsClient struct {
+	Name    string
+	Fields  []tsClientField
+	Methods []tsMethod
+}
+
+// GenerateTypeScriptSDK writes a standalone TypeScript package into
+// outputDir. The client mirrors the Go SDK tree:
+// client.libreriaB.loans.calculateLoan({...})
+func GenerateTypeScriptSDK(catalog model.Catalog, outputDir string, packageName string) error {
+	names := sdkTypeNames(catalog)
+
+	// 1. Types: enums as literal unions, structs as interfaces
+	var aliases []tsAlias
+	for _, e := range catalog.Enums {
+		var literals []string
+		for _, v := range e.Values {
+			if v.Value == "" {
+				continue
+			}
+			if e.Type == "string" {
+				literals = append(literals, strconv.Quote(v.Value))
+			} else {
+				literals = append(literals, v.Value)
+			}
+		}
+		typ := tsType(e.Type, e.Namespace, names)
+		if len(literals) > 0 {
+			typ = strings.Join(literals, " | ")
+		}
+		aliases = append(aliases, tsAlias{Name: names[e.Namespace+"."+e.Name], Type: typ})
+	}
+
+	var interfaces []tsInterface
+	for _, s := range catalog.Structs {
+		iface := tsInterface{Name: names[s.Namespace+"."+s.Name]}
+		for _, f := range s.Fields {
+			name, omitEmpty, skip := jsonFieldName(f)
+			if skip {
+				continue
+			}
+			iface.Fields = append(iface.Fields, tsField{
+				Name:     tsPropertyName(name),
+				Type:     tsType(f.Type, s.Namespace, names),
+				Optional: omitEmpty,
+			})
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	// 2. Per-method params interfaces and the nested client classes
+	root := buildNamespaceTree(catalog)
+	var clients []tsClient
+	root.walk(func(n *sdkNode) {
+		client := tsClient{Name: sdkClientName(n)}
+		for _, c := range n.Children {
+			client.Fields = append(client.Fields, tsClientField{Name: camelSegment(c.Segment), Type: sdkClientName(c)})
+		}
+		for _, svc := range n.Methods {
+			paramsName := sdkClientPrefix(n) + svc.Method + "Params"
+			params := tsInterface{Name: paramsName}
+			for _, in := range svc.Inputs {
+				params.Fields = append(params.Fields, tsField{
+					Name: tsPropertyName(in.Name),
+					Type: tsType(in.Type, svc.Namespace, names),
+				})
+			}
+			interfaces = append(interfaces, params)
+
+			result := "null"
+			if t := sdkResultType(svc); t != "" {
+				result = tsType(t, svc.Namespace, names)
+			}
+			client.Methods = append(client.Methods, tsMethod{
+				Name:       lowerFirst(svc.Method),
+				Route:      svc.Endpoint(),
+				ParamsType: paramsName,
+				ResultType: result,
+				Comments:   commentLines(svc.Description),
+			})
+		}
+		clients = append(clients, client)
+	})
+
+	srcDir := filepath.Join(outputDir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		return fmt.Errorf("could not create %s: %v", srcDir, err)
+	}
+
+	files := []struct {
+		path string
+		tmpl string
+	}{
+		{filepath.Join(outputDir, "package.json"), TSPackageTemplate},
+		{filepath.Join(outputDir, "tsconfig.json"), TSConfigTemplate},
+		{filepath.Join(srcDir, "types.ts"), TSTypesTemplate},
+		{filepath.Join(srcDir, "index.ts"), TSClientTemplate},
+	}
+	var typeNames []string
+	for _, a := range aliases {
+		typeNames = append(typeNames, a.Name)
+	}
+	for _, i := range interfaces {
+		typeNames = append(typeNames, i.Name)
+	}
+
+	data := map[string]interface{}{
+		"PackageName": packageName,
+		"TypeNames":   typeNames,
+		"Aliases":     aliases,
+		"Interfaces":  interfaces,
+		"Clients":     clients,
+	}
+	for _, file := range files {
+		if err := writeTemplate(file.path, file.tmpl, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTemplate(path string, tmpl string, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return executeTemplate(f, tmpl, data)
+}
+
+// tsPropertyName quotes keys that are not valid identifiers.
+func tsPropertyName(name string) string {
+	for i, r := range name {
+		valid := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')
+		if !valid {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+// tsType maps a Go type expression from the catalog to TypeScript.
+func tsType(goType string, namespace string, names map[string]string) string {
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		if elem == "byte" || elem == "uint8" {
+			return "string" // base64, as encoding/json does
+		}
+		inner := tsType(elem, namespace, names)
+		if strings.Contains(inner, " ") {
+			inner = "(" + inner + ")"
+		}
+		return inner + "[]"
+	}
+	if elem, ok := strings.CutPrefix(goType, "*"); ok {
+		return tsType(elem, namespace, names) + " | null"
+	}
+
+	switch goType {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "byte", "rune":
+		return "number"
+	case "time.Time":
+		return "string"
+	}
+
+	if name, ok := names[namespace+"."+goType]; ok {
+		return name
+	}
+	return "unknown"
+}
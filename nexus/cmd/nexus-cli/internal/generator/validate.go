@@ -0,0 +1,448 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// validationRule is one comma-separated item of a validate tag, e.g. gt=0.
+type validationRule struct {
+	Name string
+	Arg  string
+}
+
+// parseRules splits `required,gt=0,oneof=USD EUR`.
+func parseRules(tag string) []validationRule {
+	var rules []validationRule
+	for _, item := range strings.Split(tag, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(item, "=")
+		rules = append(rules, validationRule{Name: name, Arg: arg})
+	}
+	return rules
+}
+
+// validatorGen writes the validation code of the server: inline checks for
+// params and one validate_<alias>_<Struct> function per struct that has
+// rules, directly or in nested structs. The code compares fields directly;
+// nothing is resolved with reflection at run time.
+type validatorGen struct {
+	structs map[string]model.StructMetadata // namespace.Name
+	named   map[string]string               // namespace.Name -> underlying type of enums and named types
+	needs   map[string]bool                 // Memo of structNeedsValidation
+	funcs   map[string]string               // namespace.Name -> generated function
+	usesLen bool                            // Uses utf8.RuneCountInString
+
+	warnings []string // Rules left out of the code, printed by GenerateServer
+	problems []string // Rules that cannot be generated; see Err
+}
+
+func newValidatorGen(catalog model.Catalog) *validatorGen {
+	g := &validatorGen{
+		structs: make(map[string]model.StructMetadata),
+		named:   make(map[string]string),
+		needs:   make(map[string]bool),
+		funcs:   make(map[string]string),
+	}
+	for _, s := range catalog.Structs {
+		g.structs[s.Namespace+"."+s.Name] = s
+	}
+	for _, e := range catalog.Enums {
+		g.named[e.Namespace+"."+e.Name] = e.Type
+	}
+	for _, t := range catalog.Types {
+		g.named[t.Namespace+"."+t.Name] = t.Type
+	}
+	return g
+}
+
+// Err reports the rules that cannot be turned into checks: bounds that do
+// not parse or do not fit the field type, and rules on types whose
+// underlying type is not known. The server is not generated with them.
+func (g *validatorGen) Err() error {
+	if len(g.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid validation rules:\n  %s", strings.Join(g.problems, "\n  "))
+}
+
+// Params returns the checks of a handler's inputs, or "" when no input has
+// rules. The checks append to errs, a *RequestError.
+func (g *validatorGen) Params(svc model.ServiceEntry) string {
+	var b strings.Builder
+	for _, in := range svc.Inputs {
+		where := svc.Namespace + "." + svc.Method + " " + in.Name
+		path := strconv.Quote(in.Name)
+		if svc.Flatten {
+			path = "path" // Parameter of the generated validate method: "" when the fields were given at the top level
+		}
+		b.WriteString(g.checks("in."+in.Name, in.Type, svc.Namespace, parseRules(in.Validate), path, where))
+	}
+	return b.String()
+}
+
+// Funcs returns the generated struct validators, sorted by name.
+func (g *validatorGen) Funcs() string {
+	var funcs []string
+	for _, code := range g.funcs {
+		funcs = append(funcs, code)
+	}
+	sort.Strings(funcs) // Every entry starts with "func validate_<alias>_<Struct>"
+	return strings.Join(funcs, "\n")
+}
+
+// checks returns the code validating expr, of catalog type typ, at the
+// field path given by the Go expression path. where names the param or
+// field in warnings.
+func (g *validatorGen) checks(expr string, typ string, namespace string, rules []validationRule, path string, where string) string {
+	var b strings.Builder
+
+	// omitempty: the other rules only apply to non-zero values
+	for i, r := range rules {
+		if r.Name == "omitempty" {
+			rules = append(append([]validationRule{}, rules[:i]...), rules[i+1:]...)
+			if zero := g.zeroCheck(expr, typ, namespace); zero != "" && len(rules) > 0 {
+				return fmt.Sprintf("if !(%s) {\n%s}\n", zero, g.checks(expr, typ, namespace, rules, path, where))
+			}
+			break
+		}
+	}
+
+	// Only the first failing rule of a value is reported: its checks are
+	// chained with else.
+	var chain []string
+	switch {
+	case strings.HasPrefix(typ, "*"):
+		elem := typ[1:]
+		var rest []validationRule
+		for _, r := range rules {
+			if r.Name == "required" {
+				chain = append(chain, fmt.Sprintf("if %s == nil {\nerrs.add(%s, \"required\", \"is required\")\n}\n", expr, path))
+			} else {
+				rest = append(rest, r)
+			}
+		}
+		if _, ok := g.structs[namespace+"."+elem]; ok {
+			if fn := g.structFunc(namespace, elem); fn != "" {
+				chain = append(chain, fmt.Sprintf("if %s != nil {\n%s(%s, %s, errs)\n}\n", expr, fn, expr, path))
+			}
+			g.warnUnsupported(rest, where, typ)
+		} else if inner := g.checks("(*"+expr+")", elem, namespace, rest, path, where); inner != "" {
+			chain = append(chain, fmt.Sprintf("if %s != nil {\n%s}\n", expr, inner))
+		}
+
+	case strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map["):
+		for _, r := range rules {
+			chain = append(chain, g.lengthCheck("len("+expr+")", r, path, where, typ))
+		}
+		elem := strings.TrimPrefix(typ, "[]")
+		if strings.HasPrefix(typ, "[]") {
+			if _, ok := g.structs[namespace+"."+elem]; ok {
+				if fn := g.structFunc(namespace, elem); fn != "" {
+					fmt.Fprintf(&b, "for i := range %s {\n%s(&%s[i], %s+\"[\"+strconv.Itoa(i)+\"]\", errs)\n}\n", expr, fn, expr, path)
+				}
+			}
+		}
+
+	case g.isStruct(namespace, typ):
+		if fn := g.structFunc(namespace, typ); fn != "" {
+			fmt.Fprintf(&b, "%s(&%s, %s, errs)\n", fn, expr, path)
+		}
+		for _, r := range rules {
+			if r.Name != "required" {
+				g.warnUnsupported([]validationRule{r}, where, typ)
+			}
+		}
+
+	default:
+		basic := g.basicType(namespace, typ)
+		kind := basicKind(basic)
+		for _, r := range rules {
+			switch kind {
+			case "string":
+				chain = append(chain, g.stringCheck(expr, r, path, where, typ))
+			case "int", "uint", "float":
+				chain = append(chain, g.numberCheck(expr, basic, r, path, where, typ))
+			case "bool":
+				if r.Name == "required" {
+					chain = append(chain, fmt.Sprintf("if !%s {\nerrs.add(%s, \"required\", \"is required\")\n}\n", expr, path))
+				} else {
+					g.warnUnsupported([]validationRule{r}, where, typ)
+				}
+			default:
+				g.problems = append(g.problems, fmt.Sprintf("%s: cannot apply rule %q to type %s, whose underlying type is not known", where, r.Name, typ))
+			}
+		}
+	}
+	return chainChecks(chain) + b.String()
+}
+
+// chainChecks joins "if cond {...}" blocks with else, skipping empty ones.
+func chainChecks(checks []string) string {
+	var parts []string
+	for _, c := range checks {
+		if c != "" {
+			parts = append(parts, strings.TrimSuffix(c, "\n"))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, " else ") + "\n"
+}
+
+// structFunc returns the validator of namespace.name, generating it on
+// first use, or "" when none of its fields has rules.
+func (g *validatorGen) structFunc(namespace string, name string) string {
+	key := namespace + "." + name
+	if !g.structNeedsValidation(key, map[string]bool{}) {
+		return ""
+	}
+	alias := importAlias(namespace)
+	fn := "validate_" + alias + "_" + name
+	if _, done := g.funcs[key]; done {
+		return fn
+	}
+	g.funcs[key] = "" // Reserve the name for recursive types
+
+	var body strings.Builder
+	for _, f := range g.structs[key].Fields {
+		jsonName, _, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+		rules := parseRules(reflect.StructTag(f.Tag).Get("validate"))
+		body.WriteString(g.checks("v."+f.Name, f.Type, namespace, rules, "path+"+strconv.Quote("."+jsonName), name+"."+f.Name))
+	}
+	g.funcs[key] = fmt.Sprintf("func %s(v *%s.%s, path string, errs *RequestError) {\n%s}\n", fn, alias, name, body.String())
+	return fn
+}
+
+// structNeedsValidation reports whether a struct or a struct it contains
+// has validate tags.
+func (g *validatorGen) structNeedsValidation(key string, visiting map[string]bool) bool {
+	if needs, ok := g.needs[key]; ok {
+		return needs
+	}
+	if visiting[key] {
+		return false
+	}
+	visiting[key] = true
+
+	s := g.structs[key]
+	needs := false
+	for _, f := range s.Fields {
+		if reflect.StructTag(f.Tag).Get("validate") != "" {
+			needs = true
+			break
+		}
+		elem := strings.TrimLeft(f.Type, "*[]")
+		if _, ok := g.structs[s.Namespace+"."+elem]; ok && g.structNeedsValidation(s.Namespace+"."+elem, visiting) {
+			needs = true
+			break
+		}
+	}
+	g.needs[key] = needs
+	return needs
+}
+
+func (g *validatorGen) isStruct(namespace string, typ string) bool {
+	_, ok := g.structs[namespace+"."+typ]
+	return ok
+}
+
+// basicType resolves a named basic type of the library (type Cents int64,
+// enums) and time.Duration to their underlying type. Other types are
+// returned as they are.
+func (g *validatorGen) basicType(namespace string, typ string) string {
+	if underlying, ok := g.named[namespace+"."+typ]; ok {
+		return underlying
+	}
+	if typ == "time.Duration" {
+		return "int64"
+	}
+	return typ
+}
+
+// kind classifies a basic type or a named basic type of the library.
+func (g *validatorGen) kind(namespace string, typ string) string {
+	return basicKind(g.basicType(namespace, typ))
+}
+
+func basicKind(typ string) string {
+	switch typ {
+	case "string":
+		return "string"
+	case "bool":
+		return "bool"
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return "int"
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte", "uintptr":
+		return "uint"
+	case "float32", "float64":
+		return "float"
+	}
+	return ""
+}
+
+// zeroCheck returns a condition true when expr holds its zero value.
+func (g *validatorGen) zeroCheck(expr string, typ string, namespace string) string {
+	switch {
+	case strings.HasPrefix(typ, "*"):
+		return expr + " == nil"
+	case strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map["):
+		return "len(" + expr + ") == 0"
+	}
+	switch g.kind(namespace, typ) {
+	case "string":
+		return expr + ` == ""`
+	case "int", "uint", "float":
+		return expr + " == 0"
+	case "bool":
+		return "!" + expr
+	}
+	return ""
+}
+
+func (g *validatorGen) stringCheck(expr string, r validationRule, path string, where string, typ string) string {
+	switch r.Name {
+	case "required":
+		return fmt.Sprintf("if %s == \"\" {\nerrs.add(%s, \"required\", \"is required\")\n}\n", expr, path)
+	case "oneof":
+		values := strings.Fields(r.Arg)
+		if len(values) == 0 {
+			break
+		}
+		var conds []string
+		for _, v := range values {
+			conds = append(conds, fmt.Sprintf("%s != %s", expr, strconv.Quote(v)))
+		}
+		return fmt.Sprintf("if %s {\nerrs.add(%s, \"oneof\", %s)\n}\n", strings.Join(conds, " && "), path, strconv.Quote("must be one of "+strings.Join(values, " ")))
+	default:
+		g.usesLen = true
+		return g.lengthCheck("utf8.RuneCountInString(string("+expr+"))", r, path, where, typ)
+	}
+	g.warnUnsupported([]validationRule{r}, where, typ)
+	return ""
+}
+
+// lengthCheck applies a rule to the length of a string, slice or map.
+func (g *validatorGen) lengthCheck(lenExpr string, r validationRule, path string, where string, typ string) string {
+	if r.Name == "required" {
+		return fmt.Sprintf("if %s == 0 {\nerrs.add(%s, \"required\", \"is required\")\n}\n", lenExpr, path)
+	}
+	op, text, ok := comparison(r.Name)
+	if !ok {
+		g.warnUnsupported([]validationRule{r}, where, typ)
+		return ""
+	}
+	n, err := strconv.Atoi(r.Arg)
+	if err != nil || n < 0 {
+		g.invalidArg(r, r.Arg, where, typ)
+		return ""
+	}
+	return fmt.Sprintf("if !(%s %s %d) {\nerrs.add(%s, %q, %q)\n}\n", lenExpr, op, n, path, r.Name, "length must be "+text+" "+r.Arg)
+}
+
+// numberCheck applies a rule to a number whose underlying type is basic.
+// Bounds must fit basic: gt=300 on an int8 is an error, as the generated
+// comparison would not compile.
+func (g *validatorGen) numberCheck(expr string, basic string, r validationRule, path string, where string, typ string) string {
+	if r.Name == "required" {
+		return fmt.Sprintf("if %s == 0 {\nerrs.add(%s, \"required\", \"is required\")\n}\n", expr, path)
+	}
+	var values []string
+	if r.Name == "oneof" {
+		values = strings.Fields(r.Arg)
+	} else {
+		values = []string{r.Arg}
+	}
+	for _, v := range values {
+		var err error
+		switch basicKind(basic) {
+		case "int":
+			_, err = strconv.ParseInt(v, 10, bitSize(basic))
+		case "uint":
+			_, err = strconv.ParseUint(v, 10, bitSize(basic))
+		default:
+			_, err = strconv.ParseFloat(v, bitSize(basic))
+		}
+		if errors.Is(err, strconv.ErrRange) {
+			if basic != typ {
+				typ += " (" + basic + ")"
+			}
+			g.problems = append(g.problems, fmt.Sprintf("%s: %s=%s is out of range for type %s", where, r.Name, v, typ))
+			return ""
+		}
+		if err != nil {
+			g.invalidArg(r, v, where, typ)
+			return ""
+		}
+	}
+
+	if r.Name == "oneof" && len(values) > 0 {
+		var conds []string
+		for _, v := range values {
+			conds = append(conds, fmt.Sprintf("%s != %s", expr, v))
+		}
+		return fmt.Sprintf("if %s {\nerrs.add(%s, \"oneof\", %q)\n}\n", strings.Join(conds, " && "), path, "must be one of "+strings.Join(values, " "))
+	}
+	op, text, ok := comparison(r.Name)
+	if !ok {
+		g.warnUnsupported([]validationRule{r}, where, typ)
+		return ""
+	}
+	return fmt.Sprintf("if !(%s %s %s) {\nerrs.add(%s, %q, %q)\n}\n", expr, op, r.Arg, path, r.Name, "must be "+text+" "+r.Arg)
+}
+
+// bitSize returns the size of a basic numeric type; int, uint and uintptr
+// are taken as 64 bits.
+func bitSize(basic string) int {
+	switch basic {
+	case "int8", "uint8", "byte":
+		return 8
+	case "int16", "uint16":
+		return 16
+	case "int32", "uint32", "rune", "float32":
+		return 32
+	}
+	return 64
+}
+
+// comparison maps a rule to the operator that must hold and its wording.
+func comparison(rule string) (op string, text string, ok bool) {
+	switch rule {
+	case "gt":
+		return ">", "greater than", true
+	case "gte", "min":
+		return ">=", "at least", true
+	case "lt":
+		return "<", "less than", true
+	case "lte", "max":
+		return "<=", "at most", true
+	case "len", "eq":
+		return "==", "exactly", true
+	case "ne":
+		return "!=", "different from", true
+	}
+	return "", "", false
+}
+
+func (g *validatorGen) warnUnsupported(rules []validationRule, where string, typ string) {
+	for _, r := range rules {
+		g.warnings = append(g.warnings, fmt.Sprintf("%s: validation rule %q is not supported for type %s; ignored", where, r.Name, typ))
+	}
+}
+
+func (g *validatorGen) invalidArg(r validationRule, arg string, where string, typ string) {
+	g.problems = append(g.problems, fmt.Sprintf("%s: invalid argument %q for rule %q on type %s", where, arg, r.Name, typ))
+}
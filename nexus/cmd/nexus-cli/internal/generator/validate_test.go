@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+func TestValidatorNamedTypes(t *testing.T) {
+	catalog := model.Catalog{
+		Types: []model.NamedType{{Name: "Cents", Type: "int64", Namespace: "bank"}},
+	}
+	g := newValidatorGen(catalog)
+	code := g.Params(model.ServiceEntry{
+		Namespace: "bank",
+		Method:    "Pay",
+		Inputs: []model.ParamMetadata{
+			{Name: "amount", Type: "Cents", Validate: "gte=0"},
+			{Name: "timeout", Type: "time.Duration", Validate: "lte=60000000000"},
+		},
+	})
+	for _, want := range []string{"in.amount >= 0", "in.timeout <= 60000000000"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("checks do not contain %q:\n%s", want, code)
+		}
+	}
+	if err := g.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidatorRejectsBounds(t *testing.T) {
+	tests := []struct {
+		typ, rules, want string
+	}{
+		{"int8", "gt=300", "gt=300 is out of range for type int8"},
+		{"Small", "oneof=1 2 128", "oneof=128 is out of range for type Small (int8)"},
+		{"uint", "gte=-1", `invalid argument "-1" for rule "gte"`},
+		{"float32", "lt=1e39", "lt=1e39 is out of range for type float32"},
+		{"int", "gt=1.5", `invalid argument "1.5" for rule "gt"`},
+		{"big.Int", "gt=0", `cannot apply rule "gt" to type big.Int`},
+	}
+	for _, tt := range tests {
+		catalog := model.Catalog{
+			Types: []model.NamedType{{Name: "Small", Type: "int8", Namespace: "bank"}},
+		}
+		g := newValidatorGen(catalog)
+		g.Params(model.ServiceEntry{
+			Namespace: "bank",
+			Method:    "Pay",
+			Inputs:    []model.ParamMetadata{{Name: "n", Type: tt.typ, Validate: tt.rules}},
+		})
+		err := g.Err()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s %s: got %v, want an error with %q", tt.typ, tt.rules, err, tt.want)
+		}
+	}
+}
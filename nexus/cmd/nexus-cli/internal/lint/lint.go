@@ -0,0 +1,622 @@
+// Package lint checks a library against NEXUS_LIBRARY_STANDARD.md before it
+// reaches 'nexus-cli build'. It follows lib_config.json with the crawler's
+// own walk, but reports every problem it finds, with file:line
+// positions, instead of silently skipping the folder.
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/analyzer"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/generator"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Rules reported by the linter.
+const (
+	RuleConfig      = "config"
+	RuleOrphan      = "orphan"
+	RuleParse       = "parse"
+	RuleParam       = "param"
+	RuleReturn      = "return"
+	RuleSignature   = "signature"
+	RuleDoc         = "doc"
+	RuleCollision   = "collision"
+	RuleEmptyDomain = "empty-domain"
+)
+
+type Issue struct {
+	Pos      token.Position
+	Severity Severity
+	Rule     string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", i.Pos, i.Severity, i.Message, i.Rule)
+}
+
+// Result is the outcome of linting one library.
+type Result struct {
+	Module    string
+	Namespace string
+	Domains   int
+	Services  int
+	Issues    []Issue
+}
+
+// Count returns the number of issues with the given severity.
+func (r Result) Count(s Severity) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+type linter struct {
+	fset    *token.FileSet
+	res     *Result
+	aliases map[string]string           // import alias -> namespace
+	routes  map[string]string           // route namespace -> namespace
+	methods map[string][]token.Position // method name -> declarations
+	owners  map[string][]string         // method name -> namespaces
+	configs map[string][]byte           // lib_config.json contents, for positions in children
+}
+
+// Library lints the library rooted at root. The namespace is the last
+// element of the module path in go.mod, as in 'nexus-cli build'.
+func Library(root string) (Result, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return Result{}, err
+	}
+	if !info.IsDir() {
+		return Result{}, fmt.Errorf("%s is not a directory", root)
+	}
+
+	res := Result{}
+	l := &linter{
+		fset:    token.NewFileSet(),
+		res:     &res,
+		aliases: make(map[string]string),
+		routes:  make(map[string]string),
+		methods: make(map[string][]token.Position),
+		owners:  make(map[string][]string),
+		configs: make(map[string][]byte),
+	}
+
+	res.Module = l.module(root)
+	res.Namespace = filepath.Base(res.Module)
+	analyzer.Walk(root, res.Namespace, res.Module, l.visit)
+	l.checkMethodNames()
+
+	sort.SliceStable(res.Issues, func(a, b int) bool {
+		pa, pb := res.Issues[a].Pos, res.Issues[b].Pos
+		if pa.Filename != pb.Filename {
+			return pa.Filename < pb.Filename
+		}
+		if pa.Line != pb.Line {
+			return pa.Line < pb.Line
+		}
+		return pa.Column < pb.Column
+	})
+	return res, nil
+}
+
+func (l *linter) report(pos token.Position, sev Severity, rule string, format string, args ...interface{}) {
+	l.res.Issues = append(l.res.Issues, Issue{Pos: pos, Severity: sev, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// module reads the module path from go.mod. Without one, the folder name
+// is used (minus a module cache "@version" suffix).
+func (l *linter) module(root string) string {
+	gomod := filepath.Join(root, "go.mod")
+	path, err := analyzer.ReadModulePath(root)
+	if err == nil {
+		return path
+	}
+	if os.IsNotExist(err) {
+		l.report(token.Position{Filename: gomod}, Warning, RuleConfig, "no go.mod: the library cannot be installed with 'go get'")
+	} else {
+		l.report(token.Position{Filename: gomod, Line: 1}, Error, RuleConfig, "%v", err)
+	}
+	abs, _ := filepath.Abs(root)
+	name, _, _ := strings.Cut(filepath.Base(abs), "@")
+	return name
+}
+
+// visit checks one folder of analyzer.Walk, the walk of 'nexus-cli build',
+// so routePrefix and visibility are inherited exactly as the build does.
+func (l *linter) visit(f analyzer.Folder) {
+	data, err := os.ReadFile(f.ConfigFile)
+	if err != nil {
+		pos := token.Position{Filename: f.ConfigFile}
+		if f.ListedIn != "" {
+			if info, err := os.Stat(f.Dir); err != nil || !info.IsDir() {
+				return // Reported at its "domains" entry
+			}
+			pos = valuePos(f.ListedIn, l.configs[f.ListedIn], "domains", filepath.Base(f.Dir))
+		}
+		l.report(pos, Error, RuleConfig, "missing lib_config.json in %s: the folder and everything below it is invisible to Nexus", f.Dir)
+		return
+	}
+	l.configs[f.ConfigFile] = data
+
+	config, ok := l.readConfig(f.ConfigFile, data)
+	if !ok {
+		return
+	}
+	configFile := f.ConfigFile
+	configPos := token.Position{Filename: configFile, Line: 1, Column: 1}
+
+	if !config.IsDomain && !config.HasNestedDomains {
+		l.report(configPos, Warning, RuleConfig, "neither isDomain nor hasNestedDomains is set: the folder exposes nothing")
+	}
+	if config.HasNestedDomains && len(config.Domains) == 0 {
+		l.report(keyPos(configFile, data, "hasNestedDomains", "has_nested_domains"), Warning, RuleConfig, "hasNestedDomains is set but domains is empty")
+	}
+	if !config.HasNestedDomains && len(config.Domains) > 0 {
+		l.report(keyPos(configFile, data, "domains"), Error, RuleConfig, "domains are listed but hasNestedDomains is false: they are not scanned")
+	}
+
+	// Sub-domains
+	var children []string
+	listed := make(map[string]bool)
+	for _, domain := range config.Domains {
+		pos := valuePos(configFile, data, "domains", domain)
+		if listed[domain] {
+			l.report(pos, Warning, RuleConfig, "domain %q is listed twice", domain)
+			continue
+		}
+		listed[domain] = true
+
+		if domain == "" || strings.ContainsAny(domain, `/\`) || domain == "." || domain == ".." {
+			l.report(pos, Error, RuleConfig, "invalid domain %q: entries must be direct subfolder names", domain)
+			continue
+		}
+		if domain == "internal" {
+			l.report(pos, Error, RuleConfig, "domain %q: internal packages cannot be imported by the generated server", domain)
+			continue
+		}
+		sub := filepath.Join(f.Dir, domain)
+		if info, err := os.Stat(sub); err != nil || !info.IsDir() {
+			l.report(pos, Error, RuleConfig, "domain %q does not exist: no folder %s", domain, sub)
+			continue
+		}
+		if config.HasNestedDomains {
+			children = append(children, domain)
+		}
+	}
+	l.checkOrphans(f.Dir, listed)
+
+	if f.Settings.RoutePrefix != "" && !routePattern.MatchString(f.RouteNamespace) {
+		l.report(keyPos(configFile, data, "routePrefix", "route_prefix"), Error, RuleConfig, "invalid routePrefix %q: use dot-separated names of letters, digits, _ and -", f.Settings.RoutePrefix)
+	}
+
+	// Functions
+	if config.IsDomain && f.Visibility != model.VisibilityPrivate {
+		l.res.Domains++
+		l.checkAlias(f.Namespace, configPos)
+		if other, ok := l.routes[f.RouteNamespace]; ok {
+			l.report(configPos, Error, RuleCollision, "domain %s is routed under %s, like %s: the server would register the same routes twice", f.Namespace, f.RouteNamespace, other)
+		} else {
+			l.routes[f.RouteNamespace] = f.Namespace
+		}
+		l.lintDomain(f.Dir, f.Namespace, configPos, children)
+	}
+}
+
+// readConfig decodes lib_config.json with analyzer.ParseConfig and places
+// its errors and warnings in the file.
+func (l *linter) readConfig(file string, data []byte) (model.LibConfig, bool) {
+	config, warnings, err := analyzer.ParseConfig(data)
+	for _, w := range warnings {
+		l.report(keyPos(file, data, w.Key), Warning, RuleConfig, "%s", w)
+	}
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		var configErr *analyzer.ConfigError
+		switch {
+		case errors.As(err, &syntaxErr):
+			l.report(offsetPos(file, data, syntaxErr.Offset), Error, RuleConfig, "invalid JSON: %v", err)
+		case errors.As(err, &configErr):
+			l.report(keyPos(file, data, configErr.Key), Error, RuleConfig, "%v", err)
+		default:
+			l.report(token.Position{Filename: file, Line: 1, Column: 1}, Error, RuleConfig, "invalid lib_config.json: %v", err)
+		}
+		return config, false
+	}
+	return config, true
+}
+
+// checkOrphans reports subfolders that look like domains but are not
+// listed in domains, so the crawler never reaches them.
+func (l *linter) checkOrphans(dir string, listed map[string]bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || listed[name] || skipDir(name) {
+			continue
+		}
+		sub := filepath.Join(dir, name)
+		if _, err := os.Stat(filepath.Join(sub, "lib_config.json")); err == nil {
+			l.report(token.Position{Filename: sub}, Warning, RuleOrphan, "folder %s has a lib_config.json but is not listed in %s", name, filepath.Join(dir, "lib_config.json"))
+		} else if hasGoFiles(sub) {
+			l.report(token.Position{Filename: sub}, Warning, RuleOrphan, "folder %s contains Go code but is not listed in %s", name, filepath.Join(dir, "lib_config.json"))
+		}
+	}
+}
+
+var routePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+		name == "testdata" || name == "vendor" || name == "internal"
+}
+
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && isSourceFile(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSourceFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+func (l *linter) checkAlias(namespace string, pos token.Position) {
+	alias := strings.NewReplacer(".", "_", "-", "_").Replace(namespace)
+	if other, ok := l.aliases[alias]; ok && other != namespace {
+		l.report(pos, Error, RuleCollision, "namespace %s and %s share the import alias %s in the generated server", namespace, other, alias)
+		return
+	}
+	l.aliases[alias] = namespace
+}
+
+// lintDomain parses the package the way analyzer.ParseLibrary does and
+// checks every function it would expose.
+func (l *linter) lintDomain(dir string, namespace string, configPos token.Position, children []string) {
+	pkgs, err := parser.ParseDir(l.fset, dir, func(fi os.FileInfo) bool {
+		return isSourceFile(fi.Name())
+	}, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) {
+			for _, e := range list {
+				l.report(e.Pos, Error, RuleParse, "%s", e.Msg)
+			}
+		} else {
+			l.report(configPos, Error, RuleParse, "%v", err)
+		}
+		return
+	}
+	if len(pkgs) == 0 {
+		l.report(configPos, Error, RuleEmptyDomain, "isDomain is set but %s has no Go files", dir)
+		return
+	}
+	if len(pkgs) > 1 {
+		var names []string
+		for name := range pkgs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		l.report(configPos, Error, RuleParse, "%s contains several packages: %s", dir, strings.Join(names, ", "))
+		return
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	decls := make(map[string]*ast.TypeSpec)
+	var files []string
+	for name, file := range pkg.Files {
+		files = append(files, name)
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+				for _, spec := range gen.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						decls[ts.Name.Name] = ts
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(files)
+
+	sdkNames := make(map[string]string) // SDK field name -> sub-domain
+	for _, child := range children {
+		sdkNames[util.ToPascalCase(strings.ReplaceAll(child, "-", ""))] = child
+	}
+
+	exposed := 0
+	for _, name := range files {
+		for _, decl := range pkg.Files[name].Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !fn.Name.IsExported() || fn.Recv != nil {
+				continue
+			}
+			exposed++
+			l.lintFunc(fn, namespace, decls, sdkNames)
+		}
+	}
+	if exposed == 0 {
+		l.report(configPos, Warning, RuleEmptyDomain, "domain %s has no exported functions", namespace)
+	}
+	l.res.Services += exposed
+}
+
+func (l *linter) lintFunc(fn *ast.FuncDecl, namespace string, decls map[string]*ast.TypeSpec, sdkNames map[string]string) {
+	pos := l.fset.Position(fn.Name.Pos())
+	name := fn.Name.Name
+
+	if fn.Type.TypeParams != nil && len(fn.Type.TypeParams.List) > 0 {
+		l.report(pos, Error, RuleSignature, "generic function %s is not supported", name)
+		return
+	}
+
+	l.methods[name] = append(l.methods[name], pos)
+	l.owners[name] = append(l.owners[name], namespace)
+
+	if fn.Doc == nil || strings.TrimSpace(fn.Doc.Text()) == "" {
+		l.report(pos, Warning, RuleDoc, "exported function %s has no doc comment: its catalog description will be empty", name)
+	}
+	if child, ok := sdkNames[name]; ok {
+		l.report(pos, Error, RuleCollision, "function %s collides with sub-domain %q in the generated SDK (both become %s.%s)", name, child, namespace, name)
+	}
+
+	// Params
+	keys := make(map[string]string) // normalized JSON key -> param name
+	for _, field := range fn.Type.Params.List {
+		fieldPos := l.fset.Position(field.Pos())
+		typeExpr := field.Type
+		if ell, ok := typeExpr.(*ast.Ellipsis); ok {
+			l.report(fieldPos, Error, RuleParam, "%s: variadic parameter ...%s is not supported, use a slice", name, types.ExprString(ell.Elt))
+			typeExpr = ell.Elt
+		}
+		if len(field.Names) == 0 {
+			l.report(fieldPos, Error, RuleParam, "%s: unnamed parameter of type %s: Nexus derives the JSON key from the name", name, types.ExprString(typeExpr))
+		}
+		for _, n := range field.Names {
+			if n.Name == "_" {
+				l.report(l.fset.Position(n.Pos()), Error, RuleParam, "%s: blank parameter of type %s cannot be bound from JSON", name, types.ExprString(typeExpr))
+				continue
+			}
+			key := strings.ToLower(strings.ReplaceAll(util.ToSnakeCase(n.Name), "_", ""))
+			if other, ok := keys[key]; ok {
+				l.report(l.fset.Position(n.Pos()), Error, RuleCollision, "%s: parameters %s and %s bind to the same JSON key", name, other, n.Name)
+			}
+			keys[key] = n.Name
+		}
+		if problem := l.typeProblem(typeExpr, decls, true); problem != "" {
+			l.report(fieldPos, Error, RuleParam, "%s: unsupported parameter type %s: %s", name, types.ExprString(typeExpr), problem)
+		}
+	}
+
+	// Results
+	var results []ast.Expr
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			n := len(field.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				results = append(results, field.Type)
+			}
+		}
+	}
+	shapeOK := len(results) <= 1 || (len(results) == 2 && !isError(results[0]) && isError(results[1]))
+	if !shapeOK {
+		var list []string
+		for _, r := range results {
+			list = append(list, types.ExprString(r))
+		}
+		l.report(pos, Error, RuleReturn, "%s returns (%s): supported results are (), (T), (error) and (T, error)", name, strings.Join(list, ", "))
+	}
+	for _, r := range results {
+		if isError(r) {
+			continue
+		}
+		if problem := l.typeProblem(r, decls, true); problem != "" {
+			l.report(l.fset.Position(r.Pos()), Error, RuleReturn, "%s: unsupported result type %s: %s", name, types.ExprString(r), problem)
+		}
+	}
+}
+
+func isError(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// typeProblem explains why a type cannot cross the generated JSON wrapper,
+// or returns "". In signatures the generated server must also be able to
+// name the type; struct fields only need to be JSON-encodable.
+func (l *linter) typeProblem(expr ast.Expr, decls map[string]*ast.TypeSpec, signature bool) string {
+	return l.checkType(expr, decls, signature, make(map[string]bool))
+}
+
+func (l *linter) checkType(expr ast.Expr, decls map[string]*ast.TypeSpec, signature bool, seen map[string]bool) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string", "bool", "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64", "byte", "rune", "any":
+			return ""
+		case "error":
+			return "error is only supported as the last result"
+		case "complex64", "complex128", "uintptr":
+			return t.Name + " cannot be encoded as JSON"
+		}
+		spec, ok := decls[t.Name]
+		if !ok {
+			return ""
+		}
+		if signature && !t.IsExported() {
+			return "unexported type " + t.Name + " cannot be referenced by the generated server"
+		}
+		if seen[t.Name] {
+			return ""
+		}
+		seen[t.Name] = true
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range st.Fields.List {
+				if len(field.Names) > 0 && !field.Names[0].IsExported() {
+					continue
+				}
+				if problem := l.checkType(field.Type, decls, false, seen); problem != "" {
+					fieldName := types.ExprString(field.Type)
+					if len(field.Names) > 0 {
+						fieldName = field.Names[0].Name
+					}
+					return fmt.Sprintf("field %s.%s (%s)", t.Name, fieldName, problem)
+				}
+			}
+			return ""
+		}
+		return l.checkType(spec.Type, decls, false, seen)
+	case *ast.StarExpr:
+		return l.checkType(t.X, decls, signature, seen)
+	case *ast.ArrayType:
+		return l.checkType(t.Elt, decls, signature, seen)
+	case *ast.MapType:
+		if key, ok := t.Key.(*ast.Ident); !ok || !isMapKey(key.Name, decls) {
+			return "map keys must be strings or integers to be encoded as JSON"
+		}
+		return l.checkType(t.Value, decls, signature, seen)
+	case *ast.InterfaceType:
+		if t.Methods != nil && len(t.Methods.List) > 0 {
+			return "interfaces with methods cannot be decoded from JSON"
+		}
+		return ""
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && signature && !generator.ServerImports(pkg.Name) {
+			return "package " + pkg.Name + " is not imported by the generated server"
+		}
+		return ""
+	case *ast.ChanType:
+		return "channels cannot be encoded as JSON"
+	case *ast.FuncType:
+		return "functions cannot be encoded as JSON"
+	case *ast.StructType:
+		if signature {
+			return "anonymous structs are not supported, declare a named type"
+		}
+		return ""
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return "generic types are not supported"
+	}
+	return ""
+}
+
+func isMapKey(name string, decls map[string]*ast.TypeSpec) bool {
+	switch name {
+	case "string", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		return true
+	}
+	if spec, ok := decls[name]; ok {
+		if ident, ok := spec.Type.(*ast.Ident); ok {
+			return isMapKey(ident.Name, decls)
+		}
+	}
+	return false
+}
+
+// checkMethodNames warns about method names exported by several domains:
+// bare-name lookups ('nexus-cli call', /_nexus/services/{name}) become
+// ambiguous and need the full route.
+func (l *linter) checkMethodNames() {
+	for name, positions := range l.methods {
+		if len(positions) < 2 {
+			continue
+		}
+		for i, pos := range positions {
+			var others []string
+			for j, ns := range l.owners[name] {
+				if j != i {
+					others = append(others, ns+"."+name)
+				}
+			}
+			l.report(pos, Warning, RuleCollision, "%s is also exported as %s: lookups by method name are ambiguous", name, strings.Join(others, ", "))
+		}
+	}
+}
+
+// --- JSON positions ---
+
+func offsetPos(file string, data []byte, offset int64) token.Position {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return token.Position{Filename: file, Offset: int(offset), Line: line, Column: col}
+}
+
+// keyPos locates the first of keys found in a config file. The files are
+// small and flat, so a textual search is enough.
+func keyPos(file string, data []byte, keys ...string) token.Position {
+	for _, key := range keys {
+		quoted, _ := json.Marshal(key)
+		if i := bytes.Index(data, quoted); i >= 0 {
+			return offsetPos(file, data, int64(i))
+		}
+	}
+	return token.Position{Filename: file, Line: 1, Column: 1}
+}
+
+// valuePos locates a string value after key, e.g. an entry of "domains".
+func valuePos(file string, data []byte, key string, value string) token.Position {
+	quotedKey, _ := json.Marshal(key)
+	quoted, _ := json.Marshal(value)
+	start := bytes.Index(data, quotedKey)
+	if start < 0 {
+		return keyPos(file, data, key)
+	}
+	start += len(quotedKey)
+	if i := bytes.Index(data[start:], quoted); i >= 0 {
+		return offsetPos(file, data, int64(start+i))
+	}
+	return offsetPos(file, data, int64(start-len(quotedKey)))
+}
@@ -0,0 +1,115 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/analyzer"
+)
+
+// writeLibrary creates a library from a map of slash-separated paths to
+// contents and returns its root.
+func writeLibrary(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestQualifiedTypes(t *testing.T) {
+	root := writeLibrary(t, map[string]string{
+		"go.mod":          "module example.com/shop\n",
+		"lib_config.json": `{"isDomain": true}`,
+		"shop.go": `package shop
+
+import (
+	"io"
+	"math/big"
+	"time"
+)
+
+// Wait pauses for timeout.
+func Wait(timeout time.Duration) error { return nil }
+
+// Price returns the price at a time.
+func Price(at time.Time) (*big.Int, error) { return nil, nil }
+
+// Upload reads a file.
+func Upload(r io.Reader) error { return nil }
+`,
+	})
+	res, err := Library(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params []string
+	for _, i := range res.Issues {
+		if i.Rule == RuleParam || i.Rule == RuleReturn {
+			params = append(params, i.Message)
+		}
+	}
+	want := "Upload: unsupported parameter type io.Reader: package io is not imported by the generated server"
+	if len(params) != 1 || params[0] != want {
+		t.Errorf("got issues %q, want only %q", params, want)
+	}
+}
+
+// TestBuildLayout checks that lint lints the domains the build generates:
+// private domains and their children are skipped, and routePrefix settings
+// from the parent entry apply.
+func TestBuildLayout(t *testing.T) {
+	fn := func(pkg string) string {
+		return "package " + pkg + "\n\n// Ping answers.\nfunc Ping() string { return \"\" }\n"
+	}
+	root := writeLibrary(t, map[string]string{
+		"go.mod":                      "module example.com/bank\n",
+		"lib_config.json":             `{"version": 2, "hasNestedDomains": true, "domains": ["cards", {"name": "ledger", "routePrefix": "books"}, "admin"]}`,
+		"cards/lib_config.json":       `{"isDomain": true}`,
+		"cards/cards.go":              fn("cards"),
+		"ledger/lib_config.json":      `{"isDomain": true}`,
+		"ledger/ledger.go":            fn("ledger"),
+		"admin/lib_config.json":       `{"version": 2, "isDomain": true, "hasNestedDomains": true, "domains": ["users"], "visibility": "private"}`,
+		"admin/admin.go":              fn("admin"),
+		"admin/users/lib_config.json": `{"isDomain": true}`,
+		"admin/users/users.go":        fn("users"),
+	})
+	res, err := Library(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range res.Issues {
+		if i.Severity == Error {
+			t.Errorf("unexpected error: %s", i)
+		}
+	}
+
+	layout := analyzer.PlanLibrary(root, "bank", "example.com/bank", false)
+	if res.Domains != len(layout.Domains) || res.Domains != 2 {
+		t.Errorf("lint checked %d domains, the build plans %d, want 2", res.Domains, len(layout.Domains))
+	}
+	for _, d := range layout.Domains {
+		if d.Namespace == "bank.ledger" && d.RouteNamespace != "books" {
+			t.Errorf("bank.ledger is routed under %s, want books", d.RouteNamespace)
+		}
+	}
+
+	// Both domains export Ping: the warning names them by namespace
+	var collisions []string
+	for _, i := range res.Issues {
+		if i.Rule == RuleCollision {
+			collisions = append(collisions, i.Message)
+		}
+	}
+	if len(collisions) != 2 || !strings.Contains(strings.Join(collisions, "\n"), "bank.ledger.Ping") {
+		t.Errorf("got collisions %q, want Ping in bank.cards and bank.ledger", collisions)
+	}
+}
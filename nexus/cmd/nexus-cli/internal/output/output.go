@@ -0,0 +1,127 @@
+// Package output renders CLI results in machine-readable formats. JSON and
+// YAML write the versioned documents defined in the search package.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/japablazatww/nexus/nexus/model"
+	"github.com/japablazatww/nexus/nexus/search"
+)
+
+type Format string
+
+const (
+	FormatText     Format = "text" // Human-oriented output, printed by the CLI itself
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatTable    Format = "table"
+	FormatMarkdown Format = "markdown"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON, FormatYAML, FormatTable, FormatMarkdown:
+		return f, nil
+	case "yml":
+		return FormatYAML, nil
+	case "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q (expected text, json, yaml, table or markdown)", s)
+}
+
+// WriteSearch renders a search report. FormatText is not handled here.
+func WriteSearch(w io.Writer, format Format, report search.SearchReport) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, report)
+	case FormatYAML:
+		return WriteYAML(w, report)
+	case FormatTable, FormatMarkdown:
+		rows := [][]string{{"SERVICE", "MATCH", "KIND", "SCORE", "DESCRIPTION"}}
+		for _, r := range report.Results {
+			score := ""
+			if r.Score != 0 {
+				score = fmt.Sprintf("%.2f", r.Score)
+			}
+			rows = append(rows, []string{r.Namespace + "." + r.Method, r.MatchedParam, r.ParamType, score, firstLine(r.Description)})
+		}
+		return writeRows(w, format, rows)
+	}
+	return fmt.Errorf("format %q is not supported here", format)
+}
+
+// WriteServices renders the service listing. FormatText is not handled here.
+func WriteServices(w io.Writer, format Format, list search.ServiceList) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, list)
+	case FormatYAML:
+		return WriteYAML(w, list)
+	case FormatTable, FormatMarkdown:
+		rows := [][]string{{"SERVICE", "INPUTS", "OUTPUTS", "DESCRIPTION"}}
+		for _, s := range list.Services {
+			rows = append(rows, []string{s.Namespace + "." + s.Method, params(s.Inputs), params(s.Outputs), firstLine(s.Description)})
+		}
+		return writeRows(w, format, rows)
+	}
+	return fmt.Errorf("format %q is not supported here", format)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeRows prints rows (the first one is the header) as an aligned text
+// table or a GitHub-flavored markdown table.
+func writeRows(w io.Writer, format Format, rows [][]string) error {
+	if format == FormatMarkdown {
+		for i, row := range rows {
+			cells := make([]string, len(row))
+			for j, c := range row {
+				cells[j] = markdownCell(c)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+			if i == 0 {
+				fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(row)))
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func params(ps []model.ParamMetadata) string {
+	var parts []string
+	for _, p := range ps {
+		if strings.HasPrefix(p.Name, "result_") {
+			parts = append(parts, p.Type)
+		} else {
+			parts = append(parts, p.Name+" "+p.Type)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
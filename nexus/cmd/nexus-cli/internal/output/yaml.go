@@ -0,0 +1,167 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteYAML encodes v as a YAML document. Struct fields follow their json
+// tags (name, omitempty, "-") so YAML and JSON output share one schema.
+// It covers the value kinds used by the CLI documents: structs, maps with
+// string keys, slices, strings, numbers and booleans.
+func WriteYAML(w io.Writer, v interface{}) error {
+	var sb strings.Builder
+	if err := yamlValue(&sb, reflect.ValueOf(v), 0, atRoot); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type yamlField struct {
+	name  string
+	value reflect.Value
+}
+
+// Where a value starts: on its own at the document root, right after
+// "key:", or right after a list "-".
+const (
+	atRoot = iota
+	afterKey
+	afterDash
+)
+
+// yamlValue writes v; nested block lines are indented by indent levels.
+func yamlValue(sb *strings.Builder, v reflect.Value, indent int, pos int) error {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			sb.WriteString(" null\n")
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return yamlMapping(sb, structFields(v), indent, pos)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("yaml: unsupported map key type %s", v.Type().Key())
+		}
+		var fields []yamlField
+		for _, k := range v.MapKeys() {
+			fields = append(fields, yamlField{k.String(), v.MapIndex(k)})
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+		return yamlMapping(sb, fields, indent, pos)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			sb.WriteString(" []\n")
+			return nil
+		}
+		if pos != atRoot {
+			sb.WriteString("\n")
+		}
+		for i := 0; i < v.Len(); i++ {
+			sb.WriteString(strings.Repeat("  ", indent) + "-")
+			if err := yamlValue(sb, v.Index(i), indent+1, afterDash); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		sb.WriteString(" " + yamlString(v.String()) + "\n")
+	case reflect.Bool:
+		sb.WriteString(" " + strconv.FormatBool(v.Bool()) + "\n")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sb.WriteString(" " + strconv.FormatInt(v.Int(), 10) + "\n")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sb.WriteString(" " + strconv.FormatUint(v.Uint(), 10) + "\n")
+	case reflect.Float32, reflect.Float64:
+		sb.WriteString(" " + strconv.FormatFloat(v.Float(), 'g', -1, 64) + "\n")
+	default:
+		return fmt.Errorf("yaml: unsupported kind %s", v.Kind())
+	}
+	return nil
+}
+
+func yamlMapping(sb *strings.Builder, fields []yamlField, indent int, pos int) error {
+	if len(fields) == 0 {
+		sb.WriteString(" {}\n")
+		return nil
+	}
+	if pos == afterKey {
+		sb.WriteString("\n")
+	}
+	for i, f := range fields {
+		if i == 0 && pos == afterDash {
+			// The first key of a list item shares the "-" line
+			sb.WriteString(" " + f.name + ":")
+		} else {
+			sb.WriteString(strings.Repeat("  ", indent) + f.name + ":")
+		}
+		if err := yamlValue(sb, f.value, indent+1, afterKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structFields lists the exported fields of a struct under their JSON names.
+func structFields(v reflect.Value) []yamlField {
+	var fields []yamlField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		omitEmpty := false
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" && len(parts) == 1 {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				omitEmpty = omitEmpty || opt == "omitempty"
+			}
+		}
+		fv := v.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if omitEmpty && (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map) && fv.Len() == 0 {
+			continue
+		}
+		fields = append(fields, yamlField{name, fv})
+	}
+	return fields
+}
+
+// yamlString quotes s unless it is a plain scalar that YAML would read back
+// as the same string.
+func yamlString(s string) string {
+	if s == "" {
+		return `""`
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	if strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") ||
+		strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,266 @@
+// Package scaffold creates new libraries that follow NEXUS_LIBRARY_STANDARD.md:
+// the directory tree, the chain of lib_config.json files and example
+// functions.go/structures.go files for every domain.
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/util"
+)
+
+// DefaultGoVersion is written to the go directive of new libraries.
+const DefaultGoVersion = "1.23.1"
+
+// Options describes the library to create.
+type Options struct {
+	Module    string   // Go module path, e.g. github.com/acme/libreria-c
+	Dir       string   // Target directory (created if missing)
+	Domains   []string // Slash-separated domain paths, e.g. transfers/national
+	GoVersion string   // go directive of go.mod (default DefaultGoVersion)
+	Force     bool     // Overwrite existing files
+}
+
+// libConfig mirrors model.LibConfig but leaves out the keys a folder does
+// not need, like the hand-written configs of libreria-a.
+type libConfig struct {
+	IsDomain         bool     `json:"isDomain,omitempty"`
+	HasNestedDomains bool     `json:"hasNestedDomains,omitempty"`
+	Domains          []string `json:"domains,omitempty"`
+}
+
+// node is one folder of the library. A folder can expose functions
+// (isDomain) and contain sub-domains at the same time.
+type node struct {
+	name     string
+	rel      string // slash path relative to the library root
+	isDomain bool
+	children []*node
+}
+
+func (n *node) child(name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &node{name: name, rel: path.Join(n.rel, name)}
+	n.children = append(n.children, c)
+	return c
+}
+
+var segmentPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ParseDomains splits a comma-separated --domains value and validates every
+// path segment as a Go package name.
+func ParseDomains(list string) ([]string, error) {
+	var domains []string
+	for _, d := range strings.Split(list, ",") {
+		d = strings.Trim(strings.TrimSpace(d), "/")
+		if d == "" {
+			continue
+		}
+		for _, seg := range strings.Split(d, "/") {
+			if !segmentPattern.MatchString(seg) {
+				return nil, fmt.Errorf("invalid domain %q: %q is not a valid package name (use lowercase letters, digits and _)", d, seg)
+			}
+		}
+		domains = append(domains, d)
+	}
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("no domains given")
+	}
+	return domains, nil
+}
+
+// Init writes the library described by opts and returns the created files,
+// relative to opts.Dir. Nothing is written when a file already exists and
+// opts.Force is not set.
+func Init(opts Options) ([]string, error) {
+	if opts.Module == "" {
+		return nil, fmt.Errorf("module path is required")
+	}
+	if opts.GoVersion == "" {
+		opts.GoVersion = DefaultGoVersion
+	}
+
+	root := &node{}
+	for _, d := range opts.Domains {
+		n := root
+		for _, seg := range strings.Split(d, "/") {
+			n = n.child(seg)
+		}
+		n.isDomain = true
+	}
+
+	files := map[string][]byte{
+		"go.mod":     []byte(fmt.Sprintf("module %s\n\ngo %s\n", opts.Module, opts.GoVersion)),
+		".gitignore": []byte(gitignore),
+	}
+	if err := addFiles(files, root, opts.Module); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !opts.Force {
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(opts.Dir, filepath.FromSlash(name))); err == nil {
+				return nil, fmt.Errorf("%s already exists (use --force to overwrite)", filepath.Join(opts.Dir, filepath.FromSlash(name)))
+			}
+		}
+	}
+
+	for _, name := range names {
+		target := filepath.Join(opts.Dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, files[name], 0644); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+func addFiles(files map[string][]byte, n *node, module string) error {
+	cfg := libConfig{IsDomain: n.isDomain}
+	for _, c := range n.children {
+		cfg.HasNestedDomains = true
+		cfg.Domains = append(cfg.Domains, c.name)
+	}
+	data, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return err
+	}
+	files[path.Join(n.rel, "lib_config.json")] = append(data, '\n')
+
+	if n.isDomain {
+		d := newDomainData(n, module)
+		for name, tmpl := range map[string]*template.Template{
+			"functions.go":  functionsTemplate,
+			"structures.go": structuresTemplate,
+		} {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, d); err != nil {
+				return err
+			}
+			src, err := format.Source(buf.Bytes())
+			if err != nil {
+				return fmt.Errorf("formatting %s/%s: %v", n.rel, name, err)
+			}
+			files[path.Join(n.rel, name)] = src
+		}
+	}
+
+	for _, c := range n.children {
+		if err := addFiles(files, c, module); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type domainData struct {
+	Package   string // Go package name (last path segment)
+	Title     string // PascalCase prefix for the example names
+	Namespace string // Catalog namespace, e.g. libreria-c.transfers.national
+}
+
+func newDomainData(n *node, module string) domainData {
+	return domainData{
+		Package:   n.name,
+		Title:     title(n.name),
+		Namespace: path.Base(module) + "." + strings.ReplaceAll(n.rel, "/", "."),
+	}
+}
+
+// title turns a package name into an identifier prefix: foreign_exchange
+// becomes ForeignExchange.
+func title(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		b.WriteString(util.ToPascalCase(part))
+	}
+	return b.String()
+}
+
+var functionsTemplate = template.Must(template.New("functions").Parse(`// Package {{.Package}} is the {{.Namespace}} domain.
+// Nexus exposes every exported function of this package as
+// POST /{{.Namespace}}.<Function>.
+package {{.Package}}
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Get{{.Title}}Status reports whether the {{.Package}} domain is available for the given user.
+func Get{{.Title}}Status(userID string) (string, error) {
+	if userID == "" {
+		return "", errors.New("user id is required")
+	}
+	return "OPERATIONAL", nil
+}
+
+// Process{{.Title}} validates and processes a {{.Title}}Request.
+func Process{{.Title}}(req {{.Title}}Request) ({{.Title}}Response, error) {
+	if req.Amount <= 0 {
+		return {{.Title}}Response{}, errors.New("amount must be positive")
+	}
+	return {{.Title}}Response{
+		ID:      fmt.Sprintf("{{.Package}}-%s", req.UserID),
+		Status:  "ACCEPTED",
+		Message: fmt.Sprintf("processed %.2f %s", req.Amount, req.Currency),
+	}, nil
+}
+`))
+
+var structuresTemplate = template.Must(template.New("structures").Parse(`package {{.Package}}
+
+// {{.Title}}Request is the input of Process{{.Title}}.
+type {{.Title}}Request struct {
+	UserID   string  ` + "`json:\"user_id\"`" + `
+	Amount   float64 ` + "`json:\"amount\"`" + `
+	Currency string  ` + "`json:\"currency\"`" + `
+}
+
+// {{.Title}}Response is the result of Process{{.Title}}.
+type {{.Title}}Response struct {
+	ID      string ` + "`json:\"id\"`" + `
+	Status  string ` + "`json:\"status\"`" + `
+	Message string ` + "`json:\"message\"`" + `
+}
+`))
+
+const gitignore = `# Binaries
+*.exe
+*.dll
+*.so
+*.dylib
+
+# Test binary
+*.test
+
+# OS specific
+.DS_Store
+Thumbs.db
+
+# IDEs
+.vscode/
+.idea/
+`
@@ -36,6 +36,16 @@ func TypeToString(expr ast.Expr) string {
 		return "*" + TypeToString(t.X)
 	case *ast.SelectorExpr:
 		return TypeToString(t.X) + "." + t.Sel.Name
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + TypeToString(t.Elt)
+		}
+		if lit, ok := t.Len.(*ast.BasicLit); ok {
+			return "[" + lit.Value + "]" + TypeToString(t.Elt)
+		}
+		return "interface{}"
+	case *ast.MapType:
+		return "map[" + TypeToString(t.Key) + "]" + TypeToString(t.Value)
 	default:
 		return "interface{}"
 	}
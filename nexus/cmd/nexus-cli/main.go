@@ -1,20 +1,33 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/analyzer"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/browse"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/caller"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/dev"
 	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/generator"
-	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/model"
-	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/search"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/lint"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/output"
+	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/scaffold"
+	"github.com/japablazatww/nexus/nexus/model"
+	"github.com/japablazatww/nexus/nexus/search"
 )
 
 //go:embed registry.json
@@ -26,120 +39,561 @@ func main() {
 	buildDebug := buildCmd.Bool("debug", false, "Enable verbose output")
 	buildOutput := buildCmd.String("output", "", "Path to the 'nexus/generated' directory")
 	buildCatalogOnly := buildCmd.Bool("catalog-only", false, "Only update catalog, do not generate code")
+	buildProto := buildCmd.Bool("proto", false, "Also generate nexus.proto and the gRPC server (build tag nexus_grpc)")
+	buildSDK := buildCmd.String("sdk", "go", "Comma-separated SDK languages to generate: go, ts, python")
+	buildSDKOut := buildCmd.String("sdk-out", "", "Base directory for non-Go SDKs, one subfolder per language (default: ./sdk)")
+	buildGenerators := buildCmd.String("generators", strings.Join(generator.DefaultGenerators, ","), "Comma-separated generators to run (use 'list' to show them)")
+	buildTemplates := buildCmd.String("templates", "", "Comma-separated directories of *.tmpl files rendered as extra generators")
+	buildNoCache := buildCmd.Bool("no-cache", false, "Re-download and re-parse every library, ignoring ~/.nexus/cache")
 
 	searchCmd := flag.NewFlagSet("search", flag.ExitOnError)
 	searchParam := searchCmd.String("search-param", "", "Search service by parameter name")
+	searchQuery := searchCmd.String("query", "", "Fuzzy full-text search over methods, namespaces, descriptions, params and struct fields (or pass the words as arguments)")
+	searchInputType := searchCmd.String("input-type", "", "Find services taking these types (comma-separated, e.g. string,TransferRequest)")
+	searchOutputType := searchCmd.String("output-type", "", "Find services returning these types (comma-separated, e.g. LoanResponse)")
+	searchLimit := searchCmd.Int("limit", 20, "Maximum number of fuzzy search results (0 = all)")
+	searchFormat := searchCmd.String("format", "text", "Output format: text, json, yaml, table, markdown")
 	searchDebug := searchCmd.Bool("debug", false, "Enable verbose output")
 
+	browseCmd := flag.NewFlagSet("browse", flag.ExitOnError)
+	browseURL := browseCmd.String("url", "http://localhost:8080", "Nexus server URL used in copied snippets")
+	browseDebug := browseCmd.Bool("debug", false, "Enable verbose output")
+
+	callCmd := flag.NewFlagSet("call", flag.ExitOnError)
+	var callParams, callHeaders stringList
+	callCmd.Var(&callParams, "param", "Service param as key=value; repeatable. Keys may be struct paths (req.amount) or struct fields (amount)")
+	callCmd.Var(&callHeaders, "header", "Extra HTTP header as 'Name: value'; repeatable")
+	callURL := callCmd.String("url", envOr("NEXUS_URL", "http://localhost:8080"), "Nexus server base URL (env NEXUS_URL)")
+	callData := callCmd.String("data", "", "Read params as JSON from a file, or '-' for stdin ({\"params\": {...}} or the params object)")
+	callTimeout := callCmd.Duration("timeout", 30*time.Second, "Request timeout")
+	callRaw := callCmd.Bool("raw", false, "Print the response body as received")
+	callDebug := callCmd.Bool("debug", false, "Enable verbose output")
+
+	initCmd := flag.NewFlagSet("init-lib", flag.ExitOnError)
+	initDomains := initCmd.String("domains", "", "Comma-separated domain paths to create, e.g. transfers/national,system")
+	initDir := initCmd.String("dir", "", "Target directory (default: last element of the module path)")
+	initForce := initCmd.Bool("force", false, "Overwrite existing files")
+
+	devCmd := flag.NewFlagSet("dev", flag.ExitOnError)
+	var devLibs stringList
+	devCmd.Var(&devLibs, "lib", "Local library checkout to watch; repeatable")
+	devOutput := devCmd.String("output", "", "Path to the 'nexus/generated' directory (default: nexus/generated of the current module)")
+	devServer := devCmd.String("server", "./nexus", "Server main package, relative to the module root")
+	devInterval := devCmd.Duration("interval", 500*time.Millisecond, "Polling interval for source changes")
+	devGenerators := devCmd.String("generators", strings.Join(generator.DefaultGenerators, ","), "Comma-separated generators to run on changes")
+	devDebug := devCmd.Bool("debug", false, "Enable verbose output")
+
+	lintCmd := flag.NewFlagSet("lint", flag.ExitOnError)
+	lintStrict := lintCmd.Bool("strict", false, "Exit non-zero on warnings too")
+
 	dumpCmd := flag.NewFlagSet("dump-catalog", flag.ExitOnError)
 	dumpDebug := dumpCmd.Bool("debug", false, "Enable verbose output")
+	dumpFormat := dumpCmd.String("format", "json", "Output format: json (raw catalog), yaml, table, markdown")
 
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: nexus-cli <command> [arguments]")
-		fmt.Println("Commands: build, search, dump-catalog")
+		fmt.Println("Commands: build, search, browse, call, dev, init-lib, lint, dump-catalog")
 		os.Exit(1)
 	}
 
 	switch os.Args[1] {
 	case "build":
 		buildCmd.Parse(os.Args[2:])
-		runBuild(*buildDebug, *buildOutput, *buildCatalogOnly)
+		sdkSet := false
+		buildCmd.Visit(func(f *flag.Flag) {
+			if f.Name == "sdk" {
+				sdkSet = true
+			}
+		})
+		if *buildGenerators == "list" {
+			listGenerators()
+			return
+		}
+		generators, err := selectGenerators(*buildGenerators, *buildTemplates, *buildSDK, sdkSet, *buildProto)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		runBuild(os.Stdout, *buildDebug, *buildOutput, *buildCatalogOnly, generators, *buildSDKOut, *buildNoCache)
 	case "search":
-		searchCmd.Parse(os.Args[2:])
-		runSearch(*searchParam, *searchDebug)
+		words := parseInterleaved(searchCmd, os.Args[2:])
+		runSearch(*searchParam, fuzzyQuery(*searchQuery, words), *searchInputType, *searchOutputType, *searchLimit, mustFormat(*searchFormat), *searchDebug)
+	case "browse":
+		browseCmd.Parse(os.Args[2:])
+		runBrowse(*browseURL, *browseDebug)
+	case "call":
+		args := parseInterleaved(callCmd, os.Args[2:])
+		if len(args) != 1 {
+			fmt.Println("Usage: nexus-cli call <namespace.Method> [--param key=value ...] [--data file|-]")
+			os.Exit(1)
+		}
+		runCall(args[0], callParams, *callData, callHeaders, *callURL, *callTimeout, *callRaw, *callDebug)
+	case "init-lib":
+		args := parseInterleaved(initCmd, os.Args[2:])
+		if len(args) != 1 || *initDomains == "" {
+			fmt.Println("Usage: nexus-cli init-lib <module> --domains transfers/national,transfers/international,system [--dir path] [--force]")
+			os.Exit(1)
+		}
+		runInitLib(args[0], *initDomains, *initDir, *initForce)
+	case "dev":
+		devCmd.Parse(os.Args[2:])
+		if len(devLibs) == 0 {
+			fmt.Println("Usage: nexus-cli dev --lib ../libreria-a [--lib ...] [--output nexus/generated] [--server ./nexus]")
+			os.Exit(1)
+		}
+		generators, err := selectGenerators(*devGenerators, "", "", false, false)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		runDev(devLibs, *devOutput, *devServer, *devInterval, generators, *devDebug)
+	case "lint":
+		args := parseInterleaved(lintCmd, os.Args[2:])
+		if len(args) != 1 {
+			fmt.Println("Usage: nexus-cli lint <library path> [--strict]")
+			os.Exit(1)
+		}
+		runLint(args[0], *lintStrict)
 	case "dump-catalog":
 		dumpCmd.Parse(os.Args[2:])
-		runDump(*dumpDebug)
+		runDump(mustFormat(*dumpFormat), *dumpDebug)
 	default:
 		// Smart-Run search?
 		if strings.HasPrefix(os.Args[1], "-") {
-			searchCmd.Parse(os.Args[1:])
-			runSearch(*searchParam, *searchDebug)
+			words := parseInterleaved(searchCmd, os.Args[1:])
+			runSearch(*searchParam, fuzzyQuery(*searchQuery, words), *searchInputType, *searchOutputType, *searchLimit, mustFormat(*searchFormat), *searchDebug)
 		} else {
-			fmt.Println("Unknown command. Expected 'build', 'search', or 'dump-catalog'.")
+			fmt.Println("Unknown command. Expected 'build', 'search', 'browse', 'call', 'dev', 'init-lib', 'lint', or 'dump-catalog'.")
 			os.Exit(1)
 		}
 	}
 }
 
-func runDump(debug bool) {
+func runDump(format output.Format, debug bool) {
 	path := search.ResolveDefaultCatalog()
 	if debug {
-		fmt.Printf("Reading catalog from: %s\n", path)
+		fmt.Fprintf(os.Stderr, "Reading catalog from: %s\n", path)
 	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		fmt.Printf("Error reading catalog: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println(string(data))
+	if format == output.FormatJSON || format == output.FormatText {
+		fmt.Println(string(data))
+		return
+	}
+
+	var catalog model.Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		fmt.Printf("Error reading catalog: %v\n", err)
+		os.Exit(1)
+	}
+	if format == output.FormatYAML {
+		err = output.WriteYAML(os.Stdout, catalog)
+	} else {
+		err = output.WriteServices(os.Stdout, format, search.NewServiceList(catalog.Services))
+	}
+	if err != nil {
+		fmt.Printf("Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func mustFormat(s string) output.Format {
+	format, err := output.ParseFormat(s)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return format
 }
 
 // --- Search Logic ---
 
-func runSearch(query string, debug bool) {
+// loadCatalog reads the global catalog, running a full build first when it
+// is missing or invalid. Progress and debug messages go to out.
+func loadCatalog(out io.Writer, debug bool) model.Catalog {
 	// 1. Resolve Catalog Path
 	catalogPath := search.ResolveDefaultCatalog()
 	if debug {
-		fmt.Printf("DEBUG: Using catalog path: %s\n", catalogPath)
+		fmt.Fprintf(out, "DEBUG: Using catalog path: %s\n", catalogPath)
 	}
 
 	// 2. Auto-Discovery Check
 	catalog, err := search.LoadCatalog(catalogPath)
 	if err != nil {
-		fmt.Println("Catalog not found or invalid. Running auto-discovery...")
-		runBuild(debug, "", false) // Propagate debug, no output override, full build
+		fmt.Fprintln(out, "Catalog not found or invalid. Running auto-discovery...")
+		runBuild(out, debug, "", false, generator.DefaultGenerators, "", false) // Propagate debug, no output override, full build
 		// Re-read
 		catalog, err = search.LoadCatalog(catalogPath)
 		if err != nil {
-			fmt.Printf("Error: Could not build catalog: %v\n", err)
+			fmt.Fprintf(out, "Error: Could not build catalog: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
 	if debug {
-		fmt.Printf("DEBUG: Catalog loaded. %d services found.\n", len(catalog.Services))
+		fmt.Fprintf(out, "DEBUG: Catalog loaded. %d services found.\n", len(catalog.Services))
+	}
+	return catalog
+}
+
+// parseInterleaved parses flags that may appear after positional arguments
+// (the flag package stops at the first one) and returns the positionals.
+func parseInterleaved(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// fuzzyQuery joins --query with any positional words: nexus-cli search user balance
+func fuzzyQuery(flagValue string, args []string) string {
+	return strings.TrimSpace(flagValue + " " + strings.Join(args, " "))
+}
+
+func runSearch(query string, fuzzy string, inputType string, outputType string, limit int, format output.Format, debug bool) {
+	if format != output.FormatText {
+		// Machine-readable formats own stdout: progress and debug output
+		// (including an auto-discovery build) go to stderr.
+		catalog := loadCatalog(os.Stderr, debug)
+		if err := writeSearch(os.Stdout, format, catalog, query, fuzzy, inputType, outputType, limit); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printSearch(os.Stdout, loadCatalog(os.Stdout, debug), query, fuzzy, inputType, outputType, limit, debug)
+}
+
+// writeSearch writes the results of a search, or the service list without
+// one, in a machine-readable format.
+func writeSearch(w io.Writer, format output.Format, catalog model.Catalog, query string, fuzzy string, inputType string, outputType string, limit int) error {
+	switch {
+	case query != "":
+		mode, results := "param", search.SearchByParam(catalog, query)
+		if len(results) == 0 {
+			mode, results = "fuzzy", search.Fuzzy(catalog, query, limit)
+		}
+		return output.WriteSearch(w, format, search.NewSearchReport(mode, query, results))
+	case inputType != "" || outputType != "":
+		sig := "(" + inputType + ") -> (" + outputType + ")"
+		return output.WriteSearch(w, format, search.NewSearchReport("signature", sig, search.SearchByType(catalog, inputType, outputType)))
+	case fuzzy != "":
+		return output.WriteSearch(w, format, search.NewSearchReport("fuzzy", fuzzy, search.Fuzzy(catalog, fuzzy, limit)))
 	}
+	return output.WriteServices(w, format, search.NewServiceList(catalog.Services))
+}
 
-	// 4. Search Execution
+// printSearch writes the results of a search, or the service list without
+// one, as text.
+func printSearch(w io.Writer, catalog model.Catalog, query string, fuzzy string, inputType string, outputType string, limit int, debug bool) {
 	if query != "" {
 		if debug {
-			fmt.Printf("DEBUG: Searching for param '%s'...\n", query)
+			fmt.Fprintf(w, "DEBUG: Searching for param '%s'...\n", query)
 		}
 		results := search.SearchByParam(catalog, query)
 		if len(results) == 0 {
-			fmt.Println("No services found with that parameter.")
+			fmt.Fprintln(w, "No services found with that parameter.")
+			if similar := search.Fuzzy(catalog, query, limit); len(similar) > 0 {
+				fmt.Fprintln(w)
+				printRankedResults(w, similar, query)
+			}
+		} else {
+			fmt.Fprintf(w, "Found %d services with parameter '%s':\n", len(results), query)
+			for _, res := range results {
+				fmt.Fprintf(w, "- %s.%s\n", res.Namespace, res.Method)
+				fmt.Fprintf(w, "  Match: %s (%s)\n", res.MatchedParam, res.ParamType)
+				if res.Description != "" {
+					fmt.Fprintf(w, "  Description: %s\n", res.Description)
+				}
+			}
+		}
+	} else if inputType != "" || outputType != "" {
+		if debug {
+			fmt.Fprintf(w, "DEBUG: Searching by signature: inputs '%s', outputs '%s'...\n", inputType, outputType)
+		}
+		results := search.SearchByType(catalog, inputType, outputType)
+		if len(results) == 0 {
+			fmt.Fprintln(w, "No services found with that signature.")
 		} else {
-			fmt.Printf("Found %d services with parameter '%s':\n", len(results), query)
+			fmt.Fprintf(w, "Found %d services with that signature:\n", len(results))
 			for _, res := range results {
-				fmt.Printf("- %s.%s\n", res.Namespace, res.Method)
-				fmt.Printf("  Match: %s (%s)\n", res.MatchedParam, res.ParamType)
+				fmt.Fprintf(w, "- %s.%s\n", res.Namespace, res.Method)
+				fmt.Fprintf(w, "  Signature: %s\n", res.MatchedParam)
+				for _, reason := range res.Reasons {
+					fmt.Fprintf(w, "  Match: %s\n", reason)
+				}
 				if res.Description != "" {
-					fmt.Printf("  Description: %s\n", res.Description)
+					fmt.Fprintf(w, "  Description: %s\n", res.Description)
 				}
 			}
 		}
+	} else if fuzzy != "" {
+		if debug {
+			fmt.Fprintf(w, "DEBUG: Fuzzy search for '%s' (limit %d)...\n", fuzzy, limit)
+		}
+		results := search.Fuzzy(catalog, fuzzy, limit)
+		if len(results) == 0 {
+			fmt.Fprintln(w, "No services match that query.")
+		} else {
+			printRankedResults(w, results, fuzzy)
+		}
 	} else {
 		// List all by default
-		fmt.Println("Available Services:")
+		fmt.Fprintln(w, "Available Services:")
 		for _, s := range catalog.Services {
-			fmt.Printf("- %s.%s\n  %s\n", s.Namespace, s.Method, s.Description)
+			fmt.Fprintf(w, "- %s.%s\n  %s\n", s.Namespace, s.Method, s.Description)
 			if len(s.Inputs) > 0 {
-				fmt.Println("  Inputs:")
+				fmt.Fprintln(w, "  Inputs:")
 				for _, in := range s.Inputs {
-					fmt.Printf("    - %s (%s)\n", in.Name, in.Type)
+					fmt.Fprintf(w, "    - %s (%s)\n", in.Name, in.Type)
 				}
 			}
 			if len(s.Outputs) > 0 {
-				fmt.Println("  Outputs:")
+				fmt.Fprintln(w, "  Outputs:")
 				for _, out := range s.Outputs {
-					fmt.Printf("    - %s (%s)\n", out.Name, out.Type)
+					fmt.Fprintf(w, "    - %s (%s)\n", out.Name, out.Type)
 				}
 			}
 		}
 	}
 }
 
+func printRankedResults(w io.Writer, results []model.SearchResult, query string) {
+	fmt.Fprintf(w, "Found %d services matching '%s' (best first):\n", len(results), query)
+	for _, res := range results {
+		fmt.Fprintf(w, "- %s.%s  (score %.2f)\n", res.Namespace, res.Method, res.Score)
+		for _, reason := range res.Reasons {
+			fmt.Fprintf(w, "  Match: %s\n", reason)
+		}
+		if res.Description != "" {
+			fmt.Fprintf(w, "  Description: %s\n", res.Description)
+		}
+	}
+}
+
+// --- Browse ---
+
+func runBrowse(baseURL string, debug bool) {
+	catalog := loadCatalog(os.Stdout, debug)
+	if err := browse.Run(catalog, browse.Options{BaseURL: baseURL}); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// --- Call ---
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func envOr(name string, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func runCall(name string, pairs []string, dataPath string, headerList []string, baseURL string, timeout time.Duration, raw bool, debug bool) {
+	// Keep stdout for the response body
+	catalog := loadCatalog(os.Stderr, debug)
+
+	svc, err := caller.FindService(catalog, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var data []byte
+	switch dataPath {
+	case "":
+	case "-":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(dataPath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading --data: %v\n", err)
+		os.Exit(1)
+	}
+
+	params, warnings, err := caller.BuildParams(catalog, svc, data, pairs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	headers := make(map[string]string)
+	for _, h := range headerList {
+		k, v, ok := strings.Cut(h, ":")
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: invalid --header %q: expected 'Name: value'\n", h)
+			os.Exit(1)
+		}
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	route := svc.Endpoint()
+	if debug {
+		body, _ := json.Marshal(map[string]interface{}{"params": params})
+		fmt.Fprintf(os.Stderr, "DEBUG: POST %s/%s %s\n", strings.TrimRight(baseURL, "/"), route, body)
+	}
+
+	resp, err := caller.Call(context.Background(), caller.Options{BaseURL: baseURL, Headers: headers, Timeout: timeout}, route, params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error calling %s: %v\n", route, err)
+		os.Exit(1)
+	}
+
+	body := resp.Body
+	if !raw {
+		body = caller.Pretty(body)
+	}
+	if !bytes.HasSuffix(body, []byte("\n")) {
+		body = append(body, '\n')
+	}
+	if resp.Status != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "%s returned HTTP %d\n", route, resp.Status)
+		os.Stderr.Write(body)
+		os.Exit(1)
+	}
+	os.Stdout.Write(body)
+}
+
+// --- Library Scaffolding ---
+
+func runInitLib(module string, domainList string, dir string, force bool) {
+	domains, err := scaffold.ParseDomains(domainList)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if dir == "" {
+		dir = filepath.Base(module)
+	}
+
+	files, err := scaffold.Init(scaffold.Options{
+		Module:  module,
+		Dir:     dir,
+		Domains: domains,
+		Force:   force,
+	})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, f := range files {
+		fmt.Printf("Created: %s\n", filepath.Join(dir, filepath.FromSlash(f)))
+	}
+
+	// Crawl the result the same way 'build' will, so a broken tree shows up now.
+	var catalog model.Catalog
+	var metadata []model.FunctionMetadata
+	analyzer.CrawlLibrary(dir, filepath.Base(module), module, &catalog, &metadata, false)
+	fmt.Printf("\nLibrary %s ready: %d domains, %d services.\n", module, len(domains), len(catalog.Services))
+	for _, svc := range catalog.Services {
+		fmt.Printf("  POST /%s.%s\n", svc.Namespace, svc.Method)
+	}
+	fmt.Println("\nNext steps: publish the module and add it to registry.json, then run 'nexus-cli build'.")
+}
+
+func runLint(path string, strict bool) {
+	res, err := lint.Library(path)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, issue := range res.Issues {
+		fmt.Println(issue)
+	}
+
+	errs, warns := res.Count(lint.Error), res.Count(lint.Warning)
+	fmt.Printf("%s: %d domains, %d services, %d errors, %d warnings\n", res.Module, res.Domains, res.Services, errs, warns)
+	if errs > 0 || (strict && warns > 0) {
+		os.Exit(1)
+	}
+}
+
+// --- Dev Mode ---
+
+func runDev(libs []string, outputFlag string, serverPkg string, interval time.Duration, generators []string, debug bool) {
+	root, err := findModuleRoot()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if outputFlag == "" {
+		if info, err := os.Stat(filepath.Join(root, "nexus", "generated")); err == nil && info.IsDir() {
+			outputFlag = filepath.Join(root, "nexus", "generated")
+		}
+	}
+	outputDir, err := resolveOutputDir(outputFlag)
+	if err != nil {
+		fmt.Printf("Error resolving output directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	registry, err := analyzer.ParseRegistry(registryData)
+	if err != nil {
+		log.Fatalf("Error parsing internal registry: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = dev.Run(ctx, dev.Options{
+		Libs:       libs,
+		Registry:   registry,
+		ModuleRoot: root,
+		ServerPkg:  serverPkg,
+		OutputDir:  outputDir,
+		Generators: generators,
+		Interval:   interval,
+		Debug:      debug,
+	})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// findModuleRoot returns the closest directory above the working directory
+// that holds a go.mod.
+func findModuleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found: run 'nexus-cli dev' inside the Nexus repository")
+		}
+		dir = parent
+	}
+}
+
 // --- Path Resolution Logic ---
 
 func resolveOutputDir(flagPath string) (string, error) {
@@ -167,10 +621,104 @@ func resolveOutputDir(flagPath string) (string, error) {
 	return target, nil
 }
 
+// --- Generator Selection ---
+
+// sdkGenerators maps --sdk languages to generator names.
+var sdkGenerators = map[string]string{
+	"go":         "sdk",
+	"ts":         "sdk-ts",
+	"typescript": "sdk-ts",
+	"python":     "sdk-python",
+	"py":         "sdk-python",
+}
+
+// selectGenerators resolves the build flags into an ordered list of
+// generator names. --sdk and --proto are shorthands kept for compatibility
+// and extend whatever --generators selected.
+func selectGenerators(list string, templateDirs string, sdkLangs string, sdkSet bool, proto bool) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if sdkSet && name == "sdk" {
+			continue // --sdk decides which SDKs to build
+		}
+		add(name)
+	}
+
+	if sdkSet {
+		for _, lang := range strings.Split(sdkLangs, ",") {
+			lang = strings.TrimSpace(lang)
+			if lang == "" {
+				continue
+			}
+			name, ok := sdkGenerators[lang]
+			if !ok {
+				return nil, fmt.Errorf("unknown SDK language: %s", lang)
+			}
+			add(name)
+		}
+	}
+
+	if proto {
+		add("proto")
+	}
+
+	for _, dir := range strings.Split(templateDirs, ",") {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		g, err := generator.NewTemplateGenerator(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template directory: %v", err)
+		}
+		if err := generator.Register(g); err != nil {
+			return nil, fmt.Errorf("invalid template directory %s: %v", dir, err)
+		}
+		add(g.Name())
+	}
+
+	for _, name := range names {
+		if _, ok := generator.Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown generator %q (use --generators list)", name)
+		}
+	}
+	return names, nil
+}
+
+func listGenerators() {
+	fmt.Println("Available generators:")
+	for _, g := range generator.Available() {
+		fmt.Printf("  %-12s %s\n", g.Name(), g.Description())
+	}
+}
+
 // --- Build / Crawler Logic ---
 
-func runBuild(debug bool, outputFlag string, catalogOnly bool) {
-	fmt.Println("Starting Nexus Library Discovery (DDD Mode)...")
+// runBuild writes its progress to out: stdout for 'nexus-cli build',
+// stderr when another command needs stdout for its own output.
+func runBuild(out io.Writer, debug bool, outputFlag string, catalogOnly bool, generators []string, sdkOut string, noCache bool) {
+	fmt.Fprintln(out, "Starting Nexus Library Discovery (DDD Mode)...")
+
+	// A nil cache parses everything
+	var cache *analyzer.Cache
+	if !noCache {
+		cacheDir, err := analyzer.DefaultCacheDir()
+		if err == nil {
+			cache, err = analyzer.OpenCache(cacheDir)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "Warning: build cache disabled: %v\n", err)
+		}
+	}
 
 	// Create Temp Dir
 	tempDir, err := os.MkdirTemp("", "nexus-build")
@@ -180,88 +728,114 @@ func runBuild(debug bool, outputFlag string, catalogOnly bool) {
 	defer os.RemoveAll(tempDir)
 
 	if debug {
-		fmt.Printf("DEBUG: Temp build dir: %s\n", tempDir)
+		fmt.Fprintf(out, "DEBUG: Temp build dir: %s\n", tempDir)
 	}
 
 	execCmd(tempDir, "go", "mod", "init", "nexus-temp-builder")
 
-	var libraries []string
-	if err := json.Unmarshal(registryData, &libraries); err != nil {
+	libraries, err := analyzer.ParseRegistry(registryData)
+	if err != nil {
 		log.Fatalf("Error parsing internal registry: %v", err)
 	}
 
 	var catalog model.Catalog
 	var allMetadata []model.FunctionMetadata
 
-	for _, lib := range libraries {
-		fmt.Printf("Checking library: %s (@develop) ... ", lib)
-
-		// 1. Ensure Installed (FORCE @develop)
-		// NOTE: In production this should come from registry.json metadata
-		if err := analyzer.EnsureLibraryInstalled(tempDir, lib, "develop", debug); err != nil {
-			fmt.Printf("Failed: %v\n", err)
-			continue
-		}
+	var failed []string // Libraries that could not be installed or resolved
+	for _, entry := range libraries {
+		lib := entry.Module
+		fmt.Fprintf(out, "Checking library: %s (@%s) ... ", lib, entry.Version)
+
+		// 1. Ensure Installed (pinned versions already in the module cache are reused)
+		version := entry.Version
+		rootPath, cached := cache.ModuleDir(lib, version)
+		if !cached {
+			if err := analyzer.EnsureLibraryInstalled(tempDir, lib, entry.Version, debug); err != nil {
+				fmt.Fprintf(out, "Failed: %v\n", err)
+				failed = append(failed, lib)
+				continue
+			}
 
-		// 2. Resolve Root Path
-		rootPath, err := analyzer.ResolvePackagePath(tempDir, lib, debug)
-		if err != nil {
-			fmt.Printf("Error resolving path: %v\n", err)
-			continue
+			// 2. Resolve Root Path
+			rootPath, version, err = analyzer.ResolvePackagePath(tempDir, lib, debug)
+			if err != nil {
+				fmt.Fprintf(out, "Error resolving path: %v\n", err)
+				failed = append(failed, lib)
+				continue
+			}
+			cache.PutModuleDir(lib, entry.Version, rootPath)
 		}
 		if debug {
-			fmt.Printf("DEBUG: Root path for %s: %s\n", lib, rootPath)
+			fmt.Fprintf(out, "DEBUG: Root path for %s: %s\n", lib, rootPath)
+		} else if cached {
+			fmt.Fprintln(out, "OK (cached)")
 		} else {
-			fmt.Println("OK")
+			fmt.Fprintln(out, "OK")
 		}
 
 		// 3. Crawl Recursively
 		// Simplify namespace: github.com/japablazatww/libreria-a -> libreria-a
 		baseNamespace := filepath.Base(lib)
 		baseImportPath := lib
-		analyzer.CrawlLibrary(rootPath, baseNamespace, baseImportPath, &catalog, &allMetadata, debug)
+		var layout analyzer.Layout
+		if entry.Discovery == analyzer.DiscoveryAuto {
+			layout = analyzer.PlanAuto(rootPath, baseNamespace, baseImportPath, entry.Include, entry.Exclude, debug)
+		} else {
+			layout = analyzer.PlanLibrary(rootPath, baseNamespace, baseImportPath, debug)
+		}
+		for _, d := range layout.Domains {
+			cache.ParseDomain(d, version, debug).AddTo(&catalog, &allMetadata)
+		}
+		catalog.Domains = append(catalog.Domains, layout.Infos...)
+	}
+	if cache != nil {
+		fmt.Fprintf(out, "Build cache: %d domains reused, %d parsed\n", cache.Hits, cache.Misses)
+	}
+	// A partial catalog would drop the services of the missing libraries
+	if len(failed) > 0 {
+		log.Fatalf("Error: could not resolve %d of %d libraries: %s", len(failed), len(libraries), strings.Join(failed, ", "))
+	}
+	if err := generator.CheckEndpoints(catalog); err != nil {
+		log.Fatalf("Error: %v", err)
 	}
 
-	updateGlobalCatalog(catalog)
+	updateGlobalCatalog(out, catalog)
 
 	// 4. Generate Code
 	outputDir, err := resolveOutputDir(outputFlag)
 	if err != nil {
-		fmt.Printf("Error resolving output directory: %v\n", err)
-		fmt.Println("Tip: Use --output <path> to specify the 'nexus/generated' folder.")
+		fmt.Fprintf(out, "Error resolving output directory: %v\n", err)
+		fmt.Fprintln(out, "Tip: Use --output <path> to specify the 'nexus/generated' folder.")
 		return
 	}
 
 	// Dump Local Catalog
-	writeLocalCatalog(catalog, outputDir)
+	writeLocalCatalog(out, catalog, outputDir)
 
 	if catalogOnly {
-		fmt.Println("Catalog updated. Skipping code generation (--catalog-only).")
+		fmt.Fprintln(out, "Catalog updated. Skipping code generation (--catalog-only).")
 		return
 	}
 
-	fmt.Printf("Writing generated code to: %s\n", outputDir)
-
-	if err := generator.GenerateServer(catalog, allMetadata, outputDir); err != nil {
-		fmt.Printf("Error generating server: %v\n", err)
-	} else {
-		fmt.Println("Server code generated.")
-	}
+	fmt.Fprintf(out, "Writing generated code to: %s\n", outputDir)
 
-	if err := generator.GenerateSDK(catalog, outputDir); err != nil {
-		fmt.Printf("Error generating SDK: %v\n", err)
-	} else {
-		fmt.Println("SDK code generated.")
+	ctx := generator.Context{
+		Catalog:   catalog,
+		Metadata:  allMetadata,
+		OutputDir: outputDir,
+		SDKDir:    sdkOut,
 	}
-
-	if err := generator.GenerateTypes(catalog, outputDir); err != nil {
-		fmt.Printf("Error generating Types: %v\n", err)
-	} else {
-		fmt.Println("Types code generated.")
+	for _, name := range generators {
+		g, _ := generator.Lookup(name)
+		if err := g.Generate(ctx); err != nil {
+			fmt.Fprintf(out, "Error running generator %s: %v\n", name, err)
+		} else {
+			fmt.Fprintf(out, "Generated: %s (%s)\n", name, g.Description())
+		}
 	}
 }
 
-func updateGlobalCatalog(cat model.Catalog) {
+func updateGlobalCatalog(out io.Writer, cat model.Catalog) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		log.Fatal(err)
@@ -278,20 +852,20 @@ func updateGlobalCatalog(cat model.Catalog) {
 	encGlobal := json.NewEncoder(fGlobal)
 	encGlobal.SetIndent("", "  ")
 	encGlobal.Encode(cat)
-	fmt.Printf("Success. Catalog updated: %s\n", filepath.Join(globalDir, "catalog.json"))
+	fmt.Fprintf(out, "Success. Catalog updated: %s\n", filepath.Join(globalDir, "catalog.json"))
 }
 
-func writeLocalCatalog(cat model.Catalog, outputDir string) {
+func writeLocalCatalog(out io.Writer, cat model.Catalog, outputDir string) {
 	f, err := os.Create(filepath.Join(outputDir, "catalog.json"))
 	if err != nil {
-		fmt.Printf("Warning: Could not write catalog.json: %v\n", err)
+		fmt.Fprintf(out, "Warning: Could not write catalog.json: %v\n", err)
 		return
 	}
 	defer f.Close()
 	enc := json.NewEncoder(f)
 	enc.SetIndent("", "  ")
 	enc.Encode(cat)
-	fmt.Println("Catalog saved to generated folder.")
+	fmt.Fprintln(out, "Catalog saved to generated folder.")
 }
 
 func execCmd(dir string, name string, args ...string) error {
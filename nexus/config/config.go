@@ -0,0 +1,257 @@
+// Package config holds the settings of the Nexus server. They are read,
+// in increasing priority, from the defaults, a JSON file, NEXUS_*
+// environment variables and command-line flags:
+//
+//	{"addr": ":8443", "read_timeout": "10s", "namespaces": {"libreria-b": false}}
+//	NEXUS_ADDR=:8443 NEXUS_DISABLE=libreria-b ./nexus-server
+//	./nexus-server -addr :8443 -disable libreria-b
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the server configuration. The JSON names are the keys of the
+// config file.
+type Config struct {
+	Addr               string          `json:"addr"`
+	GRPCAddr           string          `json:"grpc_addr"` // Only with the nexus_grpc build tag
+	ReadTimeout        Duration        `json:"read_timeout"`
+	WriteTimeout       Duration        `json:"write_timeout"`
+	IdleTimeout        Duration        `json:"idle_timeout"`
+	ShutdownGrace      Duration        `json:"shutdown_grace"`       // Time to drain requests on SIGTERM
+	ShutdownDrainDelay Duration        `json:"shutdown_drain_delay"` // Time /readyz fails before the listeners close
+	MaxBodyBytes       int64           `json:"max_body_bytes"`       // 0: no limit
+	TLSCert            string          `json:"tls_cert"`
+	TLSKey             string          `json:"tls_key"`
+	CORSOrigins        []string        `json:"cors_origins"` // "*" allows any origin
+	LogLevel           string          `json:"log_level"`    // debug, info, warn or error
+	Namespaces         map[string]bool `json:"namespaces"`   // Namespace -> enabled
+}
+
+// Default returns the settings used when nothing else is given.
+func Default() Config {
+	return Config{
+		Addr:               ":8080",
+		GRPCAddr:           ":9090",
+		ReadTimeout:        Duration(30 * time.Second),
+		WriteTimeout:       Duration(30 * time.Second),
+		IdleTimeout:        Duration(2 * time.Minute),
+		ShutdownGrace:      Duration(15 * time.Second),
+		ShutdownDrainDelay: Duration(5 * time.Second),
+		MaxBodyBytes:       1 << 20,
+		LogLevel:           "info",
+	}
+}
+
+// Duration is a time.Duration written as "30s" in the config file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// setting is a value that can also be given as a flag and as an environment
+// variable: read-timeout is -read-timeout and NEXUS_READ_TIMEOUT.
+type setting struct {
+	name  string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{"addr", "listen address (default :8080)", func(c *Config, v string) error {
+		c.Addr = v
+		return nil
+	}},
+	{"grpc-addr", "gRPC listen address, with the nexus_grpc build tag (default :9090)", func(c *Config, v string) error {
+		c.GRPCAddr = v
+		return nil
+	}},
+	{"read-timeout", "maximum duration for reading a request (default 30s)", durationSetter(func(c *Config) *Duration { return &c.ReadTimeout })},
+	{"write-timeout", "maximum duration for writing a response (default 30s)", durationSetter(func(c *Config) *Duration { return &c.WriteTimeout })},
+	{"idle-timeout", "maximum idle time of a keep-alive connection (default 2m)", durationSetter(func(c *Config) *Duration { return &c.IdleTimeout })},
+	{"shutdown-grace", "time to drain in-flight requests on SIGTERM or SIGINT (default 15s)", durationSetter(func(c *Config) *Duration { return &c.ShutdownGrace })},
+	{"shutdown-drain-delay", "time /readyz reports draining before the listeners close (default 5s)", durationSetter(func(c *Config) *Duration { return &c.ShutdownDrainDelay })},
+	{"max-body-bytes", "maximum request body size, 0 for no limit (default 1048576)", func(c *Config, v string) error {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid size %q", v)
+		}
+		c.MaxBodyBytes = n
+		return nil
+	}},
+	{"tls-cert", "TLS certificate file; serves HTTPS together with -tls-key", func(c *Config, v string) error {
+		c.TLSCert = v
+		return nil
+	}},
+	{"tls-key", "TLS private key file", func(c *Config, v string) error {
+		c.TLSKey = v
+		return nil
+	}},
+	{"cors-origins", "comma-separated origins allowed by CORS, * for any", func(c *Config, v string) error {
+		c.CORSOrigins = splitList(v)
+		return nil
+	}},
+	{"log-level", "debug, info, warn or error (default info)", func(c *Config, v string) error {
+		c.LogLevel = v
+		return nil
+	}},
+	{"enable", "comma-separated namespaces to enable", namespaceSetter(true)},
+	{"disable", "comma-separated namespaces to disable, with their subdomains", namespaceSetter(false)},
+}
+
+func durationSetter(field func(c *Config) *Duration) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = Duration(d)
+		return nil
+	}
+}
+
+func namespaceSetter(enabled bool) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		for _, ns := range splitList(v) {
+			if c.Namespaces == nil {
+				c.Namespaces = make(map[string]bool)
+			}
+			c.Namespaces[ns] = enabled
+		}
+		return nil
+	}
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func envName(name string) string {
+	return "NEXUS_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Load parses args (os.Args[1:]) and returns the resulting configuration.
+// The config file is given with -config or NEXUS_CONFIG. It returns
+// flag.ErrHelp when args ask for the usage.
+func Load(args []string) (Config, error) {
+	fs := flag.NewFlagSet("nexus", flag.ContinueOnError)
+	path := fs.String("config", os.Getenv("NEXUS_CONFIG"), "JSON config file (NEXUS_CONFIG)")
+	for _, s := range settings {
+		fs.String(s.name, "", s.usage+" ("+envName(s.name)+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return Config{}, err
+		}
+	}
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(envName(s.name)); ok {
+			if err := s.set(&cfg, v); err != nil {
+				return Config{}, fmt.Errorf("%s: %v", envName(s.name), err)
+			}
+		}
+	}
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.name == f.Name && err == nil {
+				if setErr := s.set(&cfg, f.Value.String()); setErr != nil {
+					err = fmt.Errorf("-%s: %v", s.name, setErr)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// loadFile overrides c with the keys present in a JSON file. Unknown keys
+// are an error, so typos do not go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// Validate reports settings that cannot be used.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Addr == "" {
+		errs = append(errs, errors.New("addr is empty"))
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		errs = append(errs, errors.New("tls_cert and tls_key must be set together"))
+	}
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 || c.ShutdownGrace < 0 || c.ShutdownDrainDelay < 0 {
+		errs = append(errs, errors.New("timeouts cannot be negative"))
+	}
+	if c.MaxBodyBytes < 0 {
+		errs = append(errs, errors.New("max_body_bytes cannot be negative"))
+	}
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("invalid log_level %q (want debug, info, warn or error)", c.LogLevel))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether the services of a namespace are served. The
+// most specific entry of Namespaces wins: with {"libreria-a": false,
+// "libreria-a.system": true} only libreria-a.system and its subdomains
+// are served from libreria-a. Namespaces not listed are enabled.
+func (c Config) Enabled(namespace string) bool {
+	for ns := namespace; ; {
+		if enabled, ok := c.Namespaces[ns]; ok {
+			return enabled
+		}
+		i := strings.LastIndex(ns, ".")
+		if i < 0 {
+			return true
+		}
+		ns = ns[:i]
+	}
+}
@@ -0,0 +1,229 @@
+// Package discovery serves the catalog of a running Nexus server so tooling
+// and gateways can introspect a live deployment:
+//
+//	GET /_nexus/catalog                 full catalog (services, structs, enums)
+//	GET /_nexus/services                service listing (nexus.services/v1)
+//	GET /_nexus/services/{name}         one service and the types it uses
+//	GET /_nexus/search?q=balance        ranked fuzzy search (nexus.search/v1)
+//	GET /_nexus/search?param=user_id    exact parameter search
+//	GET /_nexus/search?input_type=string&output_type=float64
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+	"github.com/japablazatww/nexus/nexus/search"
+)
+
+// Prefix of every discovery route. Library namespaces never start with "_".
+const Prefix = "/_nexus"
+
+// ServiceDetail is the document served for one service.
+type ServiceDetail struct {
+	Route   string                 `json:"route"`
+	Service model.ServiceEntry     `json:"service"`
+	Structs []model.StructMetadata `json:"structs"`
+	Enums   []model.EnumMetadata   `json:"enums,omitempty"`
+}
+
+type server struct {
+	raw     []byte
+	catalog model.Catalog
+	index   *search.Index
+	err     error
+}
+
+// Register adds the discovery routes to mux. catalogJSON is the catalog the
+// server was generated from (embedded by the generated code). An invalid
+// catalog does not stop the server; the routes answer 500 instead.
+func Register(mux *http.ServeMux, catalogJSON []byte) {
+	s := &server{raw: catalogJSON}
+	if s.err = json.Unmarshal(catalogJSON, &s.catalog); s.err == nil {
+		s.index = search.NewIndex(s.catalog)
+	}
+
+	mux.HandleFunc("GET "+Prefix+"/catalog", s.handleCatalog)
+	mux.HandleFunc("GET "+Prefix+"/services", s.handleServices)
+	mux.HandleFunc("GET "+Prefix+"/services/{name}", s.handleService)
+	mux.HandleFunc("GET "+Prefix+"/search", s.handleSearch)
+}
+
+// Filter returns catalogJSON without the services, structs, enums, named
+// types, domain settings and health checks of the namespaces for which keep
+// returns false, for servers that do not expose every namespace. An invalid
+// catalog is returned as is.
+func Filter(catalogJSON []byte, keep func(namespace string) bool) []byte {
+	var catalog model.Catalog
+	if err := json.Unmarshal(catalogJSON, &catalog); err != nil {
+		return catalogJSON
+	}
+	catalog.Services = filterNamespace(catalog.Services, keep, func(svc model.ServiceEntry) string { return svc.Namespace })
+	catalog.Structs = filterNamespace(catalog.Structs, keep, func(st model.StructMetadata) string { return st.Namespace })
+	catalog.Enums = filterNamespace(catalog.Enums, keep, func(e model.EnumMetadata) string { return e.Namespace })
+	catalog.Types = filterNamespace(catalog.Types, keep, func(t model.NamedType) string { return t.Namespace })
+	catalog.Domains = filterNamespace(catalog.Domains, keep, func(d model.DomainInfo) string { return d.Namespace })
+	catalog.HealthChecks = filterNamespace(catalog.HealthChecks, keep, func(hc model.HealthCheck) string { return hc.Namespace })
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return catalogJSON
+	}
+	return data
+}
+
+func filterNamespace[T any](items []T, keep func(namespace string) bool, namespace func(T) string) []T {
+	kept := items[:0]
+	for _, item := range items {
+		if keep(namespace(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func (s *server) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	if !s.ready(w) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(s.raw)
+}
+
+func (s *server) handleServices(w http.ResponseWriter, r *http.Request) {
+	if !s.ready(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, search.NewServiceList(s.catalog.Services))
+}
+
+// handleService accepts the full route ("libreria-b.loans.CalculateLoan")
+// or a bare method name when it is unique.
+func (s *server) handleService(w http.ResponseWriter, r *http.Request) {
+	if !s.ready(w) {
+		return
+	}
+	name := r.PathValue("name")
+
+	var matches []model.ServiceEntry
+	for _, svc := range s.catalog.Services {
+		if svc.Namespace+"."+svc.Method == name || svc.Endpoint() == name {
+			matches = []model.ServiceEntry{svc}
+			break
+		}
+		if svc.Method == name {
+			matches = append(matches, svc)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		var suggestions []string
+		for _, res := range s.index.Search(name, 5) {
+			suggestions = append(suggestions, res.Namespace+"."+res.Method)
+		}
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error":       "service not found: " + name,
+			"suggestions": suggestions,
+		})
+	case 1:
+		writeJSON(w, http.StatusOK, s.detail(matches[0]))
+	default:
+		var routes []string
+		for _, svc := range matches {
+			routes = append(routes, svc.Namespace+"."+svc.Method)
+		}
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":       "ambiguous service name: " + name,
+			"suggestions": routes,
+		})
+	}
+}
+
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if !s.ready(w) {
+		return
+	}
+	q := r.URL.Query()
+
+	limit := 20
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid limit: " + v})
+			return
+		}
+		limit = n
+	}
+
+	var report search.SearchReport
+	switch {
+	case q.Get("param") != "":
+		param := q.Get("param")
+		report = search.NewSearchReport("param", param, search.SearchByParam(s.catalog, param))
+	case q.Get("input_type") != "" || q.Get("output_type") != "":
+		in, out := q.Get("input_type"), q.Get("output_type")
+		report = search.NewSearchReport("signature", "("+in+") -> ("+out+")", search.SearchByType(s.catalog, in, out))
+	case strings.TrimSpace(q.Get("q")) != "":
+		query := q.Get("q")
+		report = search.NewSearchReport("fuzzy", query, s.index.Search(query, limit))
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "missing query: use q, param, input_type or output_type"})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// detail collects the structs and enums a service uses, nested ones included.
+func (s *server) detail(svc model.ServiceEntry) ServiceDetail {
+	d := ServiceDetail{Route: svc.Endpoint(), Service: svc, Structs: []model.StructMetadata{}}
+
+	seen := make(map[string]bool)
+	var visit func(goType string)
+	visit = func(goType string) {
+		for _, name := range search.BaseTypeNames(goType) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			for _, st := range s.catalog.Structs {
+				if st.Namespace == svc.Namespace && st.Name == name {
+					d.Structs = append(d.Structs, st)
+					for _, f := range st.Fields {
+						visit(f.Type)
+					}
+				}
+			}
+			for _, e := range s.catalog.Enums {
+				if e.Namespace == svc.Namespace && e.Name == name {
+					d.Enums = append(d.Enums, e)
+				}
+			}
+		}
+	}
+	for _, p := range svc.Inputs {
+		visit(p.Type)
+	}
+	for _, p := range svc.Outputs {
+		visit(p.Type)
+	}
+	return d
+}
+
+func (s *server) ready(w http.ResponseWriter) bool {
+	if s.err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "catalog unavailable: " + s.err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
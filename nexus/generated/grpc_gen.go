@@ -0,0 +1,1525 @@
+//go:build nexus_grpc
+
+package generated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+    
+	
+	libreria_a_system "github.com/japablazatww/libreria-a/system"
+	
+	libreria_a_transfers_international "github.com/japablazatww/libreria-a/transfers/international"
+	
+	libreria_a_transfers_national "github.com/japablazatww/libreria-a/transfers/national"
+	
+	libreria_b_loans "github.com/japablazatww/libreria-b/loans"
+	
+)
+
+// NewGRPCServer returns a gRPC server exposing every service described in
+// nexus.proto. Messages are encoded by the generated functions below, so
+// no protoc output is required.
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(GRPCServerOptions(), opts...)...)
+	RegisterGRPCServices(s)
+	return s
+}
+
+// GRPCServerOptions returns the options a server needs to serve
+// RegisterGRPCServices: the generated codec and, with WithMaxBodyBytes, the
+// largest message it receives (0 for no limit, as on HTTP). Without it gRPC
+// keeps its default of 4 MiB. With WithMetrics, calls are recorded like
+// HTTP requests, labeled by the namespace and method of the catalog.
+func GRPCServerOptions(opts ...Option) []grpc.ServerOption {
+	var o serverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	serverOpts := []grpc.ServerOption{grpc.ForceServerCodec(grpcCodec{})}
+	if o.maxBodySet {
+		limit := int64(math.MaxInt32)
+		if o.maxBody > 0 {
+			limit = min(o.maxBody, limit)
+		}
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(int(limit)))
+	}
+	if o.metrics != nil {
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(o.grpcMetrics()))
+	}
+	return serverOpts
+}
+
+// grpcRoutes maps full gRPC method names to the namespace and method of
+// the catalog.
+var grpcRoutes = map[string][2]string{
+	"/nexus.LibreriaaSystemService/GetSystemStatus": {"libreria-a.system", "GetSystemStatus"},
+	"/nexus.LibreriaaTransfersNationalService/GetUserBalance": {"libreria-a.transfers.national", "GetUserBalance"},
+	"/nexus.LibreriaaTransfersNationalService/Transfer": {"libreria-a.transfers.national", "Transfer"},
+	"/nexus.LibreriaaTransfersNationalService/ComplexTransfer": {"libreria-a.transfers.national", "ComplexTransfer"},
+	"/nexus.LibreriaaTransfersInternationalService/InternationalTransfer": {"libreria-a.transfers.international", "InternationalTransfer"},
+	"/nexus.LibreriabLoansService/CalculateLoan": {"libreria-b.loans", "CalculateLoan"},
+	"/nexus.LibreriabLoansService/SayHello": {"libreria-b.loans", "SayHello"},
+	
+}
+
+// grpcMetrics records every call in o.metrics with the status code HTTP
+// would answer, so both transports share the same series.
+func (o *serverOptions) grpcMetrics() grpc.UnaryServerInterceptor {
+	for _, route := range grpcRoutes {
+		if o.serves(route[0]) {
+			o.metrics.Register(route[0], route[1])
+		}
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		route, ok := grpcRoutes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		o.metrics.Observe(route[0], route[1], grpcHTTPStatus(status.Code(err)), time.Since(start))
+		return resp, err
+	}
+}
+
+// grpcHTTPStatus maps a gRPC code to the HTTP status of the same meaning.
+func grpcHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// RegisterGRPCServices registers the services of nexus.proto on s. With
+// WithNamespaceFilter it only registers the namespaces the HTTP server
+// serves; other Options are ignored.
+func RegisterGRPCServices(s *grpc.Server, opts ...Option) {
+	var o serverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	
+	if o.serves("libreria-a.system") {
+		s.RegisterService(&grpcDescLibreriaaSystemService, struct{}{})
+	}
+	
+	if o.serves("libreria-a.transfers.national") {
+		s.RegisterService(&grpcDescLibreriaaTransfersNationalService, struct{}{})
+	}
+	
+	if o.serves("libreria-a.transfers.international") {
+		s.RegisterService(&grpcDescLibreriaaTransfersInternationalService, struct{}{})
+	}
+	
+	if o.serves("libreria-b.loans") {
+		s.RegisterService(&grpcDescLibreriabLoansService, struct{}{})
+	}
+	
+}
+
+
+var grpcDescLibreriaaSystemService = grpc.ServiceDesc{
+	ServiceName: "nexus.LibreriaaSystemService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		
+		{
+			MethodName: "GetSystemStatus",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriaaSystemService/GetSystemStatus", grpclibreria_a_system_GetSystemStatus)
+			},
+		},
+		
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nexus.proto",
+}
+
+var grpcDescLibreriaaTransfersNationalService = grpc.ServiceDesc{
+	ServiceName: "nexus.LibreriaaTransfersNationalService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		
+		{
+			MethodName: "GetUserBalance",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriaaTransfersNationalService/GetUserBalance", grpclibreria_a_transfers_national_GetUserBalance)
+			},
+		},
+		
+		{
+			MethodName: "Transfer",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriaaTransfersNationalService/Transfer", grpclibreria_a_transfers_national_Transfer)
+			},
+		},
+		
+		{
+			MethodName: "ComplexTransfer",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriaaTransfersNationalService/ComplexTransfer", grpclibreria_a_transfers_national_ComplexTransfer)
+			},
+		},
+		
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nexus.proto",
+}
+
+var grpcDescLibreriaaTransfersInternationalService = grpc.ServiceDesc{
+	ServiceName: "nexus.LibreriaaTransfersInternationalService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		
+		{
+			MethodName: "InternationalTransfer",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriaaTransfersInternationalService/InternationalTransfer", grpclibreria_a_transfers_international_InternationalTransfer)
+			},
+		},
+		
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nexus.proto",
+}
+
+var grpcDescLibreriabLoansService = grpc.ServiceDesc{
+	ServiceName: "nexus.LibreriabLoansService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		
+		{
+			MethodName: "CalculateLoan",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriabLoansService/CalculateLoan", grpclibreria_b_loans_CalculateLoan)
+			},
+		},
+		
+		{
+			MethodName: "SayHello",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return grpcUnary(srv, ctx, dec, interceptor, "/nexus.LibreriabLoansService/SayHello", grpclibreria_b_loans_SayHello)
+			},
+		},
+		
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "nexus.proto",
+}
+
+
+// --- Adapters ---
+
+func grpclibreria_a_system_GetSystemStatus(in []byte) ([]byte, error) {
+	var p pbLibreriaaSystemGetSystemStatusParams
+	if err := pbDecodeLibreriaaSystemGetSystemStatusParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0, err := libreria_a_system.GetSystemStatus(p.Code)
+	
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	
+
+	var r pbLibreriaaSystemGetSystemStatusResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriaaSystemGetSystemStatusResult(nil, &r), nil
+}
+
+func grpclibreria_a_transfers_national_GetUserBalance(in []byte) ([]byte, error) {
+	var p pbLibreriaaTransfersNationalGetUserBalanceParams
+	if err := pbDecodeLibreriaaTransfersNationalGetUserBalanceParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0, err := libreria_a_transfers_national.GetUserBalance(p.UserID, p.AccountID)
+	
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	
+
+	var r pbLibreriaaTransfersNationalGetUserBalanceResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriaaTransfersNationalGetUserBalanceResult(nil, &r), nil
+}
+
+func grpclibreria_a_transfers_national_Transfer(in []byte) ([]byte, error) {
+	var p pbLibreriaaTransfersNationalTransferParams
+	if err := pbDecodeLibreriaaTransfersNationalTransferParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0, err := libreria_a_transfers_national.Transfer(p.SourceAccount, p.DestAccount, p.Amount, p.Currency)
+	
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	
+
+	var r pbLibreriaaTransfersNationalTransferResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriaaTransfersNationalTransferResult(nil, &r), nil
+}
+
+func grpclibreria_a_transfers_national_ComplexTransfer(in []byte) ([]byte, error) {
+	var p pbLibreriaaTransfersNationalComplexTransferParams
+	if err := pbDecodeLibreriaaTransfersNationalComplexTransferParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0, err := libreria_a_transfers_national.ComplexTransfer(p.Req)
+	
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	
+
+	var r pbLibreriaaTransfersNationalComplexTransferResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriaaTransfersNationalComplexTransferResult(nil, &r), nil
+}
+
+func grpclibreria_a_transfers_international_InternationalTransfer(in []byte) ([]byte, error) {
+	var p pbLibreriaaTransfersInternationalInternationalTransferParams
+	if err := pbDecodeLibreriaaTransfersInternationalInternationalTransferParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0, err := libreria_a_transfers_international.InternationalTransfer(p.SourceAccount, p.DestIban, p.Amount, p.SwiftCode)
+	
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	
+
+	var r pbLibreriaaTransfersInternationalInternationalTransferResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriaaTransfersInternationalInternationalTransferResult(nil, &r), nil
+}
+
+func grpclibreria_b_loans_CalculateLoan(in []byte) ([]byte, error) {
+	var p pbLibreriabLoansCalculateLoanParams
+	if err := pbDecodeLibreriabLoansCalculateLoanParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0, err := libreria_b_loans.CalculateLoan(p.Req)
+	
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	
+
+	var r pbLibreriabLoansCalculateLoanResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriabLoansCalculateLoanResult(nil, &r), nil
+}
+
+func grpclibreria_b_loans_SayHello(in []byte) ([]byte, error) {
+	var p pbLibreriabLoansSayHelloParams
+	if err := pbDecodeLibreriabLoansSayHelloParams(in, &p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	
+
+	ret0 := libreria_b_loans.SayHello(p.Msn)
+	
+
+	var r pbLibreriabLoansSayHelloResult
+	
+	r.Result0 = ret0
+	
+	return pbEncodeLibreriabLoansSayHelloResult(nil, &r), nil
+}
+
+
+// --- Messages ---
+
+type pbLibreriaaSystemGetSystemStatusParams struct {
+	
+	Code string
+	
+}
+
+type pbLibreriaaSystemGetSystemStatusResult struct {
+	
+	Result0 string
+	
+}
+
+type pbLibreriaaTransfersNationalGetUserBalanceParams struct {
+	
+	UserID string
+	
+	AccountID string
+	
+}
+
+type pbLibreriaaTransfersNationalGetUserBalanceResult struct {
+	
+	Result0 float64
+	
+}
+
+type pbLibreriaaTransfersNationalTransferParams struct {
+	
+	SourceAccount string
+	
+	DestAccount string
+	
+	Amount float64
+	
+	Currency string
+	
+}
+
+type pbLibreriaaTransfersNationalTransferResult struct {
+	
+	Result0 string
+	
+}
+
+type pbLibreriaaTransfersNationalComplexTransferParams struct {
+	
+	Req libreria_a_transfers_national.TransferRequest
+	
+}
+
+type pbLibreriaaTransfersNationalComplexTransferResult struct {
+	
+	Result0 libreria_a_transfers_national.TransferResponse
+	
+}
+
+type pbLibreriaaTransfersInternationalInternationalTransferParams struct {
+	
+	SourceAccount string
+	
+	DestIban string
+	
+	Amount float64
+	
+	SwiftCode string
+	
+}
+
+type pbLibreriaaTransfersInternationalInternationalTransferResult struct {
+	
+	Result0 string
+	
+}
+
+type pbLibreriabLoansCalculateLoanParams struct {
+	
+	Req libreria_b_loans.LoanRequest
+	
+}
+
+type pbLibreriabLoansCalculateLoanResult struct {
+	
+	Result0 libreria_b_loans.LoanResponse
+	
+}
+
+type pbLibreriabLoansSayHelloParams struct {
+	
+	Msn string
+	
+}
+
+type pbLibreriabLoansSayHelloResult struct {
+	
+	Result0 string
+	
+}
+
+
+
+
+
+func pbEncodeLibreriaaTransfersNationalTransferRequest(b []byte, v *libreria_a_transfers_national.TransferRequest) []byte {
+	
+	b = pbAppendString(b, 1, v.SourceAccount)
+	
+	b = pbAppendString(b, 2, v.DestAccount)
+	
+	b = pbAppendDouble(b, 3, v.Amount)
+	
+	b = pbAppendString(b, 4, v.Currency)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalTransferRequest(b []byte, v *libreria_a_transfers_national.TransferRequest) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.SourceAccount, n, err = pbReadString(b, typ)
+		
+		case 2:
+			v.DestAccount, n, err = pbReadString(b, typ)
+		
+		case 3:
+			v.Amount, n, err = pbReadDouble(b, typ)
+		
+		case 4:
+			v.Currency, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalTransferResponse(b []byte, v *libreria_a_transfers_national.TransferResponse) []byte {
+	
+	b = pbAppendString(b, 1, v.TransactionID)
+	
+	b = pbAppendString(b, 2, v.Status)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalTransferResponse(b []byte, v *libreria_a_transfers_national.TransferResponse) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.TransactionID, n, err = pbReadString(b, typ)
+		
+		case 2:
+			v.Status, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriabLoansLoanRequest(b []byte, v *libreria_b_loans.LoanRequest) []byte {
+	
+	b = pbAppendDouble(b, 1, v.Amount)
+	
+	b = pbAppendVarint(b, 2, uint64(v.Term))
+	
+	b = pbAppendString(b, 3, v.UserType)
+	
+	return b
+}
+
+func pbDecodeLibreriabLoansLoanRequest(b []byte, v *libreria_b_loans.LoanRequest) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Amount, n, err = pbReadDouble(b, typ)
+		
+		case 2:
+			var x uint64
+			x, n, err = pbReadVarint(b, typ)
+			v.Term = int(x)
+		
+		case 3:
+			v.UserType, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriabLoansLoanResponse(b []byte, v *libreria_b_loans.LoanResponse) []byte {
+	
+	b = pbAppendBool(b, 1, v.Approved)
+	
+	b = pbAppendDouble(b, 2, v.InterestRate)
+	
+	b = pbAppendDouble(b, 3, v.MonthlyPay)
+	
+	b = pbAppendString(b, 4, v.Message)
+	
+	return b
+}
+
+func pbDecodeLibreriabLoansLoanResponse(b []byte, v *libreria_b_loans.LoanResponse) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Approved, n, err = pbReadBool(b, typ)
+		
+		case 2:
+			v.InterestRate, n, err = pbReadDouble(b, typ)
+		
+		case 3:
+			v.MonthlyPay, n, err = pbReadDouble(b, typ)
+		
+		case 4:
+			v.Message, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaSystemGetSystemStatusParams(b []byte, v *pbLibreriaaSystemGetSystemStatusParams) []byte {
+	
+	b = pbAppendString(b, 1, v.Code)
+	
+	return b
+}
+
+func pbDecodeLibreriaaSystemGetSystemStatusParams(b []byte, v *pbLibreriaaSystemGetSystemStatusParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Code, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaSystemGetSystemStatusResult(b []byte, v *pbLibreriaaSystemGetSystemStatusResult) []byte {
+	
+	b = pbAppendString(b, 1, v.Result0)
+	
+	return b
+}
+
+func pbDecodeLibreriaaSystemGetSystemStatusResult(b []byte, v *pbLibreriaaSystemGetSystemStatusResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Result0, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalGetUserBalanceParams(b []byte, v *pbLibreriaaTransfersNationalGetUserBalanceParams) []byte {
+	
+	b = pbAppendString(b, 1, v.UserID)
+	
+	b = pbAppendString(b, 2, v.AccountID)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalGetUserBalanceParams(b []byte, v *pbLibreriaaTransfersNationalGetUserBalanceParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.UserID, n, err = pbReadString(b, typ)
+		
+		case 2:
+			v.AccountID, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalGetUserBalanceResult(b []byte, v *pbLibreriaaTransfersNationalGetUserBalanceResult) []byte {
+	
+	b = pbAppendDouble(b, 1, v.Result0)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalGetUserBalanceResult(b []byte, v *pbLibreriaaTransfersNationalGetUserBalanceResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Result0, n, err = pbReadDouble(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalTransferParams(b []byte, v *pbLibreriaaTransfersNationalTransferParams) []byte {
+	
+	b = pbAppendString(b, 1, v.SourceAccount)
+	
+	b = pbAppendString(b, 2, v.DestAccount)
+	
+	b = pbAppendDouble(b, 3, v.Amount)
+	
+	b = pbAppendString(b, 4, v.Currency)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalTransferParams(b []byte, v *pbLibreriaaTransfersNationalTransferParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.SourceAccount, n, err = pbReadString(b, typ)
+		
+		case 2:
+			v.DestAccount, n, err = pbReadString(b, typ)
+		
+		case 3:
+			v.Amount, n, err = pbReadDouble(b, typ)
+		
+		case 4:
+			v.Currency, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalTransferResult(b []byte, v *pbLibreriaaTransfersNationalTransferResult) []byte {
+	
+	b = pbAppendString(b, 1, v.Result0)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalTransferResult(b []byte, v *pbLibreriaaTransfersNationalTransferResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Result0, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalComplexTransferParams(b []byte, v *pbLibreriaaTransfersNationalComplexTransferParams) []byte {
+	
+	b = pbAppendBytes(b, 1, pbEncodeLibreriaaTransfersNationalTransferRequest(nil, &v.Req))
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalComplexTransferParams(b []byte, v *pbLibreriaaTransfersNationalComplexTransferParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			var raw []byte
+			raw, n, err = pbReadBytes(b, typ)
+			if err == nil {
+				x := new(libreria_a_transfers_national.TransferRequest)
+				err = pbDecodeLibreriaaTransfersNationalTransferRequest(raw, x)
+				v.Req = *x
+			}
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersNationalComplexTransferResult(b []byte, v *pbLibreriaaTransfersNationalComplexTransferResult) []byte {
+	
+	b = pbAppendBytes(b, 1, pbEncodeLibreriaaTransfersNationalTransferResponse(nil, &v.Result0))
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersNationalComplexTransferResult(b []byte, v *pbLibreriaaTransfersNationalComplexTransferResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			var raw []byte
+			raw, n, err = pbReadBytes(b, typ)
+			if err == nil {
+				x := new(libreria_a_transfers_national.TransferResponse)
+				err = pbDecodeLibreriaaTransfersNationalTransferResponse(raw, x)
+				v.Result0 = *x
+			}
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersInternationalInternationalTransferParams(b []byte, v *pbLibreriaaTransfersInternationalInternationalTransferParams) []byte {
+	
+	b = pbAppendString(b, 1, v.SourceAccount)
+	
+	b = pbAppendString(b, 2, v.DestIban)
+	
+	b = pbAppendDouble(b, 3, v.Amount)
+	
+	b = pbAppendString(b, 4, v.SwiftCode)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersInternationalInternationalTransferParams(b []byte, v *pbLibreriaaTransfersInternationalInternationalTransferParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.SourceAccount, n, err = pbReadString(b, typ)
+		
+		case 2:
+			v.DestIban, n, err = pbReadString(b, typ)
+		
+		case 3:
+			v.Amount, n, err = pbReadDouble(b, typ)
+		
+		case 4:
+			v.SwiftCode, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriaaTransfersInternationalInternationalTransferResult(b []byte, v *pbLibreriaaTransfersInternationalInternationalTransferResult) []byte {
+	
+	b = pbAppendString(b, 1, v.Result0)
+	
+	return b
+}
+
+func pbDecodeLibreriaaTransfersInternationalInternationalTransferResult(b []byte, v *pbLibreriaaTransfersInternationalInternationalTransferResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Result0, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriabLoansCalculateLoanParams(b []byte, v *pbLibreriabLoansCalculateLoanParams) []byte {
+	
+	b = pbAppendBytes(b, 1, pbEncodeLibreriabLoansLoanRequest(nil, &v.Req))
+	
+	return b
+}
+
+func pbDecodeLibreriabLoansCalculateLoanParams(b []byte, v *pbLibreriabLoansCalculateLoanParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			var raw []byte
+			raw, n, err = pbReadBytes(b, typ)
+			if err == nil {
+				x := new(libreria_b_loans.LoanRequest)
+				err = pbDecodeLibreriabLoansLoanRequest(raw, x)
+				v.Req = *x
+			}
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriabLoansCalculateLoanResult(b []byte, v *pbLibreriabLoansCalculateLoanResult) []byte {
+	
+	b = pbAppendBytes(b, 1, pbEncodeLibreriabLoansLoanResponse(nil, &v.Result0))
+	
+	return b
+}
+
+func pbDecodeLibreriabLoansCalculateLoanResult(b []byte, v *pbLibreriabLoansCalculateLoanResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			var raw []byte
+			raw, n, err = pbReadBytes(b, typ)
+			if err == nil {
+				x := new(libreria_b_loans.LoanResponse)
+				err = pbDecodeLibreriabLoansLoanResponse(raw, x)
+				v.Result0 = *x
+			}
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriabLoansSayHelloParams(b []byte, v *pbLibreriabLoansSayHelloParams) []byte {
+	
+	b = pbAppendString(b, 1, v.Msn)
+	
+	return b
+}
+
+func pbDecodeLibreriabLoansSayHelloParams(b []byte, v *pbLibreriabLoansSayHelloParams) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Msn, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func pbEncodeLibreriabLoansSayHelloResult(b []byte, v *pbLibreriabLoansSayHelloResult) []byte {
+	
+	b = pbAppendString(b, 1, v.Result0)
+	
+	return b
+}
+
+func pbDecodeLibreriabLoansSayHelloResult(b []byte, v *pbLibreriabLoansSayHelloResult) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var err error
+		switch num {
+		
+		case 1:
+			v.Result0, n, err = pbReadString(b, typ)
+		
+		default:
+			n, err = pbSkip(num, typ, b)
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+
+// grpcMessageRoundTrips decodes a message of nexus.proto into its Go type
+// and encodes it back, by proto name. Tests compare them with the .proto.
+var grpcMessageRoundTrips = map[string]func(in []byte) ([]byte, error){
+	"LibreriaaTransfersNationalTransferRequest": func(in []byte) ([]byte, error) {
+		var v libreria_a_transfers_national.TransferRequest
+		if err := pbDecodeLibreriaaTransfersNationalTransferRequest(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalTransferRequest(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalTransferResponse": func(in []byte) ([]byte, error) {
+		var v libreria_a_transfers_national.TransferResponse
+		if err := pbDecodeLibreriaaTransfersNationalTransferResponse(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalTransferResponse(nil, &v), nil
+	},
+	"LibreriabLoansLoanRequest": func(in []byte) ([]byte, error) {
+		var v libreria_b_loans.LoanRequest
+		if err := pbDecodeLibreriabLoansLoanRequest(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriabLoansLoanRequest(nil, &v), nil
+	},
+	"LibreriabLoansLoanResponse": func(in []byte) ([]byte, error) {
+		var v libreria_b_loans.LoanResponse
+		if err := pbDecodeLibreriabLoansLoanResponse(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriabLoansLoanResponse(nil, &v), nil
+	},
+	"LibreriaaSystemGetSystemStatusParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaSystemGetSystemStatusParams
+		if err := pbDecodeLibreriaaSystemGetSystemStatusParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaSystemGetSystemStatusParams(nil, &v), nil
+	},
+	"LibreriaaSystemGetSystemStatusResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaSystemGetSystemStatusResult
+		if err := pbDecodeLibreriaaSystemGetSystemStatusResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaSystemGetSystemStatusResult(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalGetUserBalanceParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersNationalGetUserBalanceParams
+		if err := pbDecodeLibreriaaTransfersNationalGetUserBalanceParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalGetUserBalanceParams(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalGetUserBalanceResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersNationalGetUserBalanceResult
+		if err := pbDecodeLibreriaaTransfersNationalGetUserBalanceResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalGetUserBalanceResult(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalTransferParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersNationalTransferParams
+		if err := pbDecodeLibreriaaTransfersNationalTransferParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalTransferParams(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalTransferResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersNationalTransferResult
+		if err := pbDecodeLibreriaaTransfersNationalTransferResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalTransferResult(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalComplexTransferParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersNationalComplexTransferParams
+		if err := pbDecodeLibreriaaTransfersNationalComplexTransferParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalComplexTransferParams(nil, &v), nil
+	},
+	"LibreriaaTransfersNationalComplexTransferResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersNationalComplexTransferResult
+		if err := pbDecodeLibreriaaTransfersNationalComplexTransferResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersNationalComplexTransferResult(nil, &v), nil
+	},
+	"LibreriaaTransfersInternationalInternationalTransferParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersInternationalInternationalTransferParams
+		if err := pbDecodeLibreriaaTransfersInternationalInternationalTransferParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersInternationalInternationalTransferParams(nil, &v), nil
+	},
+	"LibreriaaTransfersInternationalInternationalTransferResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriaaTransfersInternationalInternationalTransferResult
+		if err := pbDecodeLibreriaaTransfersInternationalInternationalTransferResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriaaTransfersInternationalInternationalTransferResult(nil, &v), nil
+	},
+	"LibreriabLoansCalculateLoanParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriabLoansCalculateLoanParams
+		if err := pbDecodeLibreriabLoansCalculateLoanParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriabLoansCalculateLoanParams(nil, &v), nil
+	},
+	"LibreriabLoansCalculateLoanResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriabLoansCalculateLoanResult
+		if err := pbDecodeLibreriabLoansCalculateLoanResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriabLoansCalculateLoanResult(nil, &v), nil
+	},
+	"LibreriabLoansSayHelloParams": func(in []byte) ([]byte, error) {
+		var v pbLibreriabLoansSayHelloParams
+		if err := pbDecodeLibreriabLoansSayHelloParams(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriabLoansSayHelloParams(nil, &v), nil
+	},
+	"LibreriabLoansSayHelloResult": func(in []byte) ([]byte, error) {
+		var v pbLibreriabLoansSayHelloResult
+		if err := pbDecodeLibreriabLoansSayHelloResult(in, &v); err != nil {
+			return nil, err
+		}
+		return pbEncodeLibreriabLoansSayHelloResult(nil, &v), nil
+	},
+	
+}
+
+// --- Runtime ---
+
+// grpcRawMessage carries already-encoded protobuf bytes through grpc-go.
+type grpcRawMessage []byte
+
+type grpcCodec struct{}
+
+func (grpcCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(*grpcRawMessage)
+	if !ok {
+		return nil, errors.New("nexus grpc: unexpected message type")
+	}
+	return *msg, nil
+}
+
+func (grpcCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*grpcRawMessage)
+	if !ok {
+		return errors.New("nexus grpc: unexpected message type")
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+func (grpcCodec) Name() string {
+	return "proto"
+}
+
+func grpcUnary(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor, fullMethod string, call func([]byte) ([]byte, error)) (interface{}, error) {
+	var in grpcRawMessage
+	if err := dec(&in); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		out, err := call(*req.(*grpcRawMessage))
+		if err != nil {
+			return nil, err
+		}
+		msg := grpcRawMessage(out)
+		return &msg, nil
+	}
+	if interceptor == nil {
+		return handler(ctx, &in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+	return interceptor(ctx, &in, info, handler)
+}
+
+// grpcInvalidArgument is the gRPC form of the 400 answered to a
+// *RequestError: InvalidArgument with a BadRequest detail listing the
+// fields.
+func grpcInvalidArgument(errs *RequestError) error {
+	detail := &errdetails.BadRequest{}
+	for _, f := range errs.Fields {
+		detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+			Reason:      f.Rule,
+		})
+	}
+	st := status.New(codes.InvalidArgument, errs.Error())
+	if withDetail, err := st.WithDetails(detail); err == nil {
+		st = withDetail
+	}
+	return st.Err()
+}
+
+var errWireType = errors.New("nexus grpc: unexpected wire type")
+
+func pbAppendString(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func pbAppendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func pbAppendBool(b []byte, num protowire.Number, v bool) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, protowire.EncodeBool(v))
+}
+
+func pbAppendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func pbAppendDouble(b []byte, num protowire.Number, v float64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func pbAppendFloat(b []byte, num protowire.Number, v float32) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, math.Float32bits(v))
+}
+
+func pbAppendJSON(b []byte, num protowire.Number, v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return pbAppendBytes(b, num, data)
+}
+
+func pbReadString(b []byte, typ protowire.Type) (string, int, error) {
+	if typ != protowire.BytesType {
+		return "", 0, errWireType
+	}
+	v, n := protowire.ConsumeString(b)
+	if n < 0 {
+		return "", 0, protowire.ParseError(n)
+	}
+	return v, n, nil
+}
+
+func pbReadBytes(b []byte, typ protowire.Type) ([]byte, int, error) {
+	if typ != protowire.BytesType {
+		return nil, 0, errWireType
+	}
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return nil, 0, protowire.ParseError(n)
+	}
+	return append([]byte(nil), v...), n, nil
+}
+
+func pbReadVarint(b []byte, typ protowire.Type) (uint64, int, error) {
+	if typ != protowire.VarintType {
+		return 0, 0, errWireType
+	}
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, 0, protowire.ParseError(n)
+	}
+	return v, n, nil
+}
+
+func pbReadBool(b []byte, typ protowire.Type) (bool, int, error) {
+	v, n, err := pbReadVarint(b, typ)
+	return protowire.DecodeBool(v), n, err
+}
+
+func pbReadDouble(b []byte, typ protowire.Type) (float64, int, error) {
+	if typ != protowire.Fixed64Type {
+		return 0, 0, errWireType
+	}
+	v, n := protowire.ConsumeFixed64(b)
+	if n < 0 {
+		return 0, 0, protowire.ParseError(n)
+	}
+	return math.Float64frombits(v), n, nil
+}
+
+func pbReadFloat(b []byte, typ protowire.Type) (float32, int, error) {
+	if typ != protowire.Fixed32Type {
+		return 0, 0, errWireType
+	}
+	v, n := protowire.ConsumeFixed32(b)
+	if n < 0 {
+		return 0, 0, protowire.ParseError(n)
+	}
+	return math.Float32frombits(v), n, nil
+}
+
+// Times are RFC 3339 strings; the zero time is the empty string.
+func pbAppendTime(b []byte, num protowire.Number, v time.Time) []byte {
+	if v.IsZero() {
+		return pbAppendString(b, num, "")
+	}
+	return pbAppendString(b, num, v.Format(time.RFC3339Nano))
+}
+
+func pbReadTime(b []byte, typ protowire.Type) (time.Time, int, error) {
+	s, n, err := pbReadString(b, typ)
+	if err != nil || s == "" {
+		return time.Time{}, n, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, n, err
+}
+
+// pbDecodeFields calls field for each field of a message, which reads it
+// and returns its length. Map entries are decoded with it.
+func pbDecodeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// pbSkip returns the length of a field the message does not know.
+func pbSkip(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	n := protowire.ConsumeFieldValue(num, typ, b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return n, nil
+}
+
+func pbReadJSON(b []byte, typ protowire.Type, v interface{}) (int, error) {
+	data, n, err := pbReadBytes(b, typ)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return n, nil
+	}
+	return n, json.Unmarshal(data, v)
+}
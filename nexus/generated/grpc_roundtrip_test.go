@@ -0,0 +1,237 @@
+//go:build nexus_grpc
+
+package generated
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TestGRPCCodecMatchesProto checks the generated codec against nexus.proto.
+// Every message is filled by dynamicpb and encoded by the protobuf library,
+// decoded into its Go type and encoded again by the generated functions,
+// and decoded by the library, which must give back the same message.
+//
+//	go test -tags nexus_grpc ./generated -run GRPCCodec
+func TestGRPCCodecMatchesProto(t *testing.T) {
+	compiler := protocompile.Compiler{
+		Resolver:       &protocompile.SourceResolver{},
+		SourceInfoMode: protocompile.SourceInfoStandard, // Keeps the "JSON encoded" comments
+	}
+	files, err := compiler.Compile(context.Background(), "nexus.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := files[0].Messages()
+	if messages.Len() != len(grpcMessageRoundTrips) {
+		t.Errorf("nexus.proto declares %d messages, grpc_gen.go encodes %d", messages.Len(), len(grpcMessageRoundTrips))
+	}
+
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		t.Run(string(md.Name()), func(t *testing.T) {
+			roundTrip, ok := grpcMessageRoundTrips[string(md.Name())]
+			if !ok {
+				t.Fatalf("grpc_gen.go has no codec for %s", md.Name())
+			}
+			want := dynamicpb.NewMessage(md)
+			if err := fill(want, 0); err != nil {
+				t.Fatal(err)
+			}
+			in, err := proto.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := roundTrip(in)
+			if err != nil {
+				t.Fatalf("generated decode: %v", err)
+			}
+			got := dynamicpb.NewMessage(md)
+			if err := proto.Unmarshal(out, got); err != nil {
+				t.Fatalf("decoding the generated encoding: %v", err)
+			}
+			pruneEmpty(want)
+			pruneEmpty(got)
+			if !proto.Equal(want, got) {
+				t.Errorf("round trip changed the message\nsent: %s\ngot:  %s", prototext.Format(want), prototext.Format(got))
+			}
+		})
+	}
+}
+
+// maxFillDepth stops self-referencing messages.
+const maxFillDepth = 4
+
+// fill sets every field of m to a value that is not the default. Numbers
+// are the ones that break a wrong encoding: int64 beyond 2^53, negative
+// varints and unsigned values over 2^63, chosen to fit the narrowest Go
+// type behind each proto type (int8 for int32, uint8 for uint32).
+func fill(m protoreflect.Message, depth int) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		switch {
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			for j := 0; j < 2; j++ {
+				v, err := fieldValue(list.NewElement, fd, depth)
+				if err != nil {
+					return err
+				}
+				list.Append(v)
+			}
+		case fd.IsMap():
+			entries := m.Mutable(fd).Map()
+			for j, key := range []string{"a", "b"} {
+				k := scalarValue(fd.MapKey(), j)
+				if fd.MapKey().Kind() == protoreflect.StringKind {
+					k = protoreflect.ValueOfString(key)
+				}
+				v, err := fieldValue(entries.NewValue, fd.MapValue(), depth)
+				if err != nil {
+					return err
+				}
+				if timestamp(fd) {
+					v = timestampValue(j)
+				}
+				entries.Set(k.MapKey(), v)
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			if depth >= maxFillDepth {
+				continue
+			}
+			if err := fill(m.Mutable(fd).Message(), depth+1); err != nil {
+				return err
+			}
+		case jsonEncoded(fd):
+			v, err := jsonZero(m.Descriptor(), fd)
+			if err != nil {
+				return err
+			}
+			m.Set(fd, v)
+		case timestamp(fd):
+			m.Set(fd, timestampValue(0))
+		default:
+			m.Set(fd, scalarValue(fd, 0))
+		}
+	}
+	return nil
+}
+
+func fieldValue(newMessage func() protoreflect.Value, fd protoreflect.FieldDescriptor, depth int) (protoreflect.Value, error) {
+	if timestamp(fd) {
+		return timestampValue(1), nil
+	}
+	if fd.Kind() != protoreflect.MessageKind {
+		return scalarValue(fd, 1), nil
+	}
+	v := newMessage()
+	if depth < maxFillDepth {
+		if err := fill(v.Message(), depth+1); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+// pruneEmpty clears the empty singular messages. A Go struct held by value
+// is encoded even when it is zero, but fill leaves it unset past
+// maxFillDepth; both mean the same to the generated code.
+func pruneEmpty(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Kind() == protoreflect.MessageKind {
+				for i := 0; i < v.List().Len(); i++ {
+					pruneEmpty(v.List().Get(i).Message())
+				}
+			}
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, e protoreflect.Value) bool {
+					pruneEmpty(e.Message())
+					return true
+				})
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			pruneEmpty(v.Message())
+			empty := true
+			v.Message().Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+				empty = false
+				return false
+			})
+			if empty {
+				m.Clear(fd)
+			}
+		}
+		return true
+	})
+}
+
+// scalarValue returns a non-default value of a scalar field; n varies it.
+func scalarValue(fd protoreflect.FieldDescriptor, n int) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(-(1 << 62) + 7 + int64(n))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(-100 + int32(n))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(1<<63 + 9 + uint64(n))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(200 + uint32(n))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(1234567.890625 + float64(n))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(1.5 + float32(n))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte{0, 0xff, byte(n)})
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		return protoreflect.ValueOfEnum(values.Get(values.Len() - 1).Number())
+	}
+	return protoreflect.ValueOfString("ñandú " + string(fd.Name()) + strings.Repeat("!", n))
+}
+
+// jsonEncoded reports the string fields that carry a Go value proto cannot
+// describe, as JSON.
+func jsonEncoded(fd protoreflect.FieldDescriptor) bool {
+	loc := fd.ParentFile().SourceLocations().ByDescriptor(fd)
+	return strings.TrimSpace(loc.TrailingComments) == "JSON encoded"
+}
+
+// timestamp reports the string fields that carry a time.Time. The comment
+// of a map field describes its values.
+func timestamp(fd protoreflect.FieldDescriptor) bool {
+	loc := fd.ParentFile().SourceLocations().ByDescriptor(fd)
+	return strings.TrimSpace(loc.TrailingComments) == "RFC 3339 timestamp"
+}
+
+// timestampValue returns a timestamp in the form the generated code
+// writes, with nanoseconds; n varies it.
+func timestampValue(n int) protoreflect.Value {
+	return protoreflect.ValueOfString(fmt.Sprintf("2024-02-29T12:30:%02d.123456789Z", 45+n))
+}
+
+// jsonZero returns the JSON the generated code writes for the zero value of
+// a JSON encoded field, the only content valid for every Go type.
+func jsonZero(md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	out, err := grpcMessageRoundTrips[string(md.Name())](nil)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	zero := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(out, zero); err != nil {
+		return protoreflect.Value{}, err
+	}
+	return zero.Get(fd), nil
+}
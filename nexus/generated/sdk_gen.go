@@ -41,22 +41,14 @@ func (t *httpTransport) Call(method string, req GenericRequest) (interface{}, er
 // --- Structs ---
 
 
-type LibreriaaTransfersNationalClient struct {
+type LibreriaaSystemClient struct {
 	transport Transport
 	
 }
 
 
-func (c *LibreriaaTransfersNationalClient) GetUserBalance(req GenericRequest) (interface{}, error) {
-	return c.transport.Call("libreria-a.transfers.national.GetUserBalance", req)
-}
-
-func (c *LibreriaaTransfersNationalClient) Transfer(req GenericRequest) (interface{}, error) {
-	return c.transport.Call("libreria-a.transfers.national.Transfer", req)
-}
-
-func (c *LibreriaaTransfersNationalClient) ComplexTransfer(req GenericRequest) (interface{}, error) {
-	return c.transport.Call("libreria-a.transfers.national.ComplexTransfer", req)
+func (c *LibreriaaSystemClient) GetSystemStatus(req GenericRequest) (interface{}, error) {
+	return c.transport.Call("libreria-a.system.GetSystemStatus", req)
 }
 
 
@@ -71,35 +63,43 @@ func (c *LibreriaaTransfersInternationalClient) InternationalTransfer(req Generi
 }
 
 
-type LibreriaaTransfersClient struct {
+type LibreriaaTransfersNationalClient struct {
 	transport Transport
 	
-	National *LibreriaaTransfersNationalClient
-	
-	International *LibreriaaTransfersInternationalClient
-	
 }
 
 
+func (c *LibreriaaTransfersNationalClient) GetUserBalance(req GenericRequest) (interface{}, error) {
+	return c.transport.Call("libreria-a.transfers.national.GetUserBalance", req)
+}
 
-type LibreriaaSystemClient struct {
-	transport Transport
-	
+func (c *LibreriaaTransfersNationalClient) Transfer(req GenericRequest) (interface{}, error) {
+	return c.transport.Call("libreria-a.transfers.national.Transfer", req)
 }
 
+func (c *LibreriaaTransfersNationalClient) ComplexTransfer(req GenericRequest) (interface{}, error) {
+	return c.transport.Call("libreria-a.transfers.national.ComplexTransfer", req)
+}
 
-func (c *LibreriaaSystemClient) GetSystemStatus(req GenericRequest) (interface{}, error) {
-	return c.transport.Call("libreria-a.system.GetSystemStatus", req)
+
+type LibreriaaTransfersClient struct {
+	transport Transport
+	
+	International *LibreriaaTransfersInternationalClient
+	
+	National *LibreriaaTransfersNationalClient
+	
 }
 
 
+
 type LibreriaaClient struct {
 	transport Transport
 	
-	Transfers *LibreriaaTransfersClient
-	
 	System *LibreriaaSystemClient
 	
+	Transfers *LibreriaaTransfersClient
+	
 }
 
 
@@ -141,20 +141,25 @@ type Client struct {
 
 
 func NewClient(baseURL string) *Client {
-	t := &httpTransport{
+	return NewClientWithTransport(&httpTransport{
 		BaseURL: baseURL,
 		Client:  &http.Client{},
-	}
+	})
+}
+
+// NewClientWithTransport builds the client tree on top of any Transport,
+// e.g. NewInProcessTransport() or NewMockTransport() in tests.
+func NewClientWithTransport(t Transport) *Client {
 	c := &Client{transport: t}
 	
 	// Dynamic Init
-	c.Libreriab = &LibreriabClient{transport: t}
-	c.Libreriab.Loans = &LibreriabLoansClient{transport: t}
 	c.Libreriaa = &LibreriaaClient{transport: t}
 	c.Libreriaa.System = &LibreriaaSystemClient{transport: t}
 	c.Libreriaa.Transfers = &LibreriaaTransfersClient{transport: t}
-	c.Libreriaa.Transfers.National = &LibreriaaTransfersNationalClient{transport: t}
 	c.Libreriaa.Transfers.International = &LibreriaaTransfersInternationalClient{transport: t}
+	c.Libreriaa.Transfers.National = &LibreriaaTransfersNationalClient{transport: t}
+	c.Libreriab = &LibreriabClient{transport: t}
+	c.Libreriab.Loans = &LibreriabLoansClient{transport: t}
 
 	return c
 }
@@ -0,0 +1,167 @@
+package generated
+
+import (
+	"encoding/json"
+	"testing"
+
+	libreria_b_loans "github.com/japablazatww/libreria-b/loans"
+)
+
+// The benchmarks compare the decode path of the generated handlers with
+// the one they replaced: the body decoded into map[string]interface{} and
+// every complex param converted with json.Marshal and json.Unmarshal.
+// The struct case is faster with a third of the allocations; the int64
+// case takes the same time but allocates about 100 more bytes, for the
+// json.RawMessage values of the params map.
+//
+//	go test ./generated -run '^$' -bench Decode -benchmem
+
+var loanBody = []byte(`{"params":{"req":{"amount":150000.5,"term":36,"user_type":"PREMIUM"}}}`)
+
+// accountNumber does not fit in a float64: 2^53 + 1.
+const accountNumber int64 = 9007199254740993
+
+var accountBody = []byte(`{"params":{"account_number":9007199254740993}}`)
+
+// An int64 account-number method like the ones of core-banking libraries,
+// declared as server_gen.go would declare it.
+var paramsBenchAccount = newParamTable("account_number")
+
+type requestBenchAccount struct {
+	account_number int64
+}
+
+// legacyDecode is the decode path before the request structs, minus the
+// fuzzy key lookup.
+func legacyDecode(body []byte, name string, v interface{}) error {
+	var req struct {
+		Params map[string]interface{} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return err
+	}
+	jsonBody, err := json.Marshal(req.Params[name])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBody, v)
+}
+
+// decodeLoan follows wrapperlibreria_b_loans_CalculateLoan up to the call.
+func decodeLoan(body []byte) (requestlibreria_b_loans_CalculateLoan, error) {
+	var in requestlibreria_b_loans_CalculateLoan
+	var req rawRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return in, err
+	}
+	args, err := paramslibreria_b_loans_CalculateLoan.bind(req.Params, MatchFuzzy)
+	if err != nil {
+		return in, err
+	}
+	flat, err := paramslibreria_b_loans_CalculateLoan.flatten(args)
+	if err != nil {
+		return in, err
+	}
+	path := "req"
+	if flat != nil {
+		args[0], path = flat, ""
+	}
+	errs := &RequestError{}
+	decodeParam(errs, path, args[0], &in.req)
+	if len(errs.Fields) > 0 {
+		return in, errs
+	}
+	return in, nil
+}
+
+func decodeAccount(body []byte) (requestBenchAccount, error) {
+	var in requestBenchAccount
+	var req rawRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return in, err
+	}
+	args, err := paramsBenchAccount.bind(req.Params, MatchFuzzy)
+	if err != nil {
+		return in, err
+	}
+	errs := &RequestError{}
+	decodeParam(errs, "account_number", args[0], &in.account_number)
+	if len(errs.Fields) > 0 {
+		return in, errs
+	}
+	return in, nil
+}
+
+func TestDecodeLoan(t *testing.T) {
+	in, err := decodeLoan(loanBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := libreria_b_loans.LoanRequest{Amount: 150000.5, Term: 36, UserType: "PREMIUM"}
+	if in.req != want {
+		t.Fatalf("got %+v, want %+v", in.req, want)
+	}
+}
+
+func TestDecodeAccountNumberPrecision(t *testing.T) {
+	in, err := decodeAccount(accountBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.account_number != accountNumber {
+		t.Fatalf("got %d, want %d", in.account_number, accountNumber)
+	}
+
+	// The legacy path went through float64 and lost the last digit
+	var legacy int64
+	if err := legacyDecode(accountBody, "account_number", &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy == accountNumber {
+		t.Fatalf("legacy decode kept %d; the comparison no longer shows the precision loss", legacy)
+	}
+}
+
+func BenchmarkDecodeLoan(b *testing.B) {
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var req libreria_b_loans.LoanRequest
+			if err := legacyDecode(loanBody, "req", &req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("request-struct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeLoan(loanBody); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeAccountNumber(b *testing.B) {
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var n int64
+			if err := legacyDecode(accountBody, "account_number", &n); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("request-struct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			in, err := decodeAccount(accountBody)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if in.account_number != accountNumber {
+				b.Fatalf("got %d, want %d", in.account_number, accountNumber)
+			}
+		}
+	})
+}
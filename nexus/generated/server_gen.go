@@ -1,9 +1,21 @@
 package generated
 
 import (
+	"bytes"
+	_ "embed"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	
+	"github.com/japablazatww/nexus/nexus/discovery"
+	"github.com/japablazatww/nexus/nexus/metrics"
     
 	
 	libreria_a_system "github.com/japablazatww/libreria-a/system"
@@ -16,28 +28,650 @@ import (
 	
 )
 
-func RegisterHandlers(mux *http.ServeMux) {
+// catalogJSON is the catalog this server was generated from, served by the
+// discovery API under /_nexus/.
+//go:embed catalog.json
+var catalogJSON []byte
+
+// MatchMode decides which keys of "params" bind to a function input.
+type MatchMode int
+
+const (
+	// MatchFuzzy ignores case and underscores: user_id, userId and USERID
+	// all bind to user_id. It is the default.
+	MatchFuzzy MatchMode = iota
+	// MatchSnakeCamel accepts the catalog name and its camelCase form:
+	// user_id or userId. Acronyms split per letter in the catalog are also
+	// accepted whole: user_i_d (userID) binds user_i_d, userID, user_id
+	// and userId.
+	MatchSnakeCamel
+	// MatchStrict only accepts the catalog name: user_id.
+	MatchStrict
+)
+
+// Option configures RegisterHandlers.
+type Option func(*serverOptions)
+
+type serverOptions struct {
+	match      MatchMode
+	services   map[string]MatchMode // Route -> mode
+	maxBody    int64
+	maxBodySet bool // WithMaxBodyBytes was given; gRPC keeps its own default otherwise
+	namespaces func(namespace string) bool
+	metrics    *metrics.Recorder
+}
+
+// WithMatchMode sets the matching mode of every service.
+func WithMatchMode(mode MatchMode) Option {
+	return func(o *serverOptions) {
+		o.match = mode
+	}
+}
+
+// WithServiceMatchMode overrides the matching mode of one service, by route
+// ("libreria-a.system.GetSystemStatus", or a Method* constant).
+func WithServiceMatchMode(route string, mode MatchMode) Option {
+	return func(o *serverOptions) {
+		if o.services == nil {
+			o.services = make(map[string]MatchMode)
+		}
+		o.services[route] = mode
+	}
+}
+
+// WithMaxBodyBytes rejects request bodies larger than n bytes with 413
+// Request Entity Too Large. 0 means no limit, the default.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *serverOptions) {
+		o.maxBody, o.maxBodySet = n, true
+	}
+}
+
+// WithNamespaceFilter only registers the services of the namespaces for
+// which enabled returns true. The discovery API lists only those services.
+func WithNamespaceFilter(enabled func(namespace string) bool) Option {
+	return func(o *serverOptions) {
+		o.namespaces = enabled
+	}
+}
+
+// WithMetrics records the requests of every route in m, labeled by
+// namespace and method; serve m at /metrics.
+func WithMetrics(m *metrics.Recorder) Option {
+	return func(o *serverOptions) {
+		o.metrics = m
+	}
+}
+
+func (o *serverOptions) instrument(namespace string, method string, h http.HandlerFunc) http.HandlerFunc {
+	if o.metrics == nil {
+		return h
+	}
+	return o.metrics.Instrument(namespace, method, h)
+}
+
+func (o *serverOptions) serves(namespace string) bool {
+	return o.namespaces == nil || o.namespaces(namespace)
+}
+
+func (o *serverOptions) matchModeFor(route string) MatchMode {
+	if mode, ok := o.services[route]; ok {
+		return mode
+	}
+	return o.match
+}
+
+// handlers serves the routes of one RegisterHandlers call with its
+// options, so servers in the same process keep their own settings.
+type handlers struct {
+	options serverOptions
+}
+
+func RegisterHandlers(mux *http.ServeMux, opts ...Option) {
+	h := &handlers{}
+	for _, opt := range opts {
+		opt(&h.options)
+	}
+
 	
-	mux.HandleFunc("/libreria-a.system.GetSystemStatus", handlelibreria_a_system_GetSystemStatus)
+	if h.options.serves("libreria-a.system") {
+		mux.HandleFunc("/libreria-a.system.GetSystemStatus", h.options.instrument("libreria-a.system", "GetSystemStatus", h.handlelibreria_a_system_GetSystemStatus))
+	}
+	
+	if h.options.serves("libreria-a.transfers.national") {
+		mux.HandleFunc("/libreria-a.transfers.national.GetUserBalance", h.options.instrument("libreria-a.transfers.national", "GetUserBalance", h.handlelibreria_a_transfers_national_GetUserBalance))
+	}
+	
+	if h.options.serves("libreria-a.transfers.national") {
+		mux.HandleFunc("/libreria-a.transfers.national.Transfer", h.options.instrument("libreria-a.transfers.national", "Transfer", h.handlelibreria_a_transfers_national_Transfer))
+	}
+	
+	if h.options.serves("libreria-a.transfers.national") {
+		mux.HandleFunc("/libreria-a.transfers.national.ComplexTransfer", h.options.instrument("libreria-a.transfers.national", "ComplexTransfer", h.handlelibreria_a_transfers_national_ComplexTransfer))
+	}
+	
+	if h.options.serves("libreria-a.transfers.international") {
+		mux.HandleFunc("/libreria-a.transfers.international.InternationalTransfer", h.options.instrument("libreria-a.transfers.international", "InternationalTransfer", h.handlelibreria_a_transfers_international_InternationalTransfer))
+	}
+	
+	if h.options.serves("libreria-b.loans") {
+		mux.HandleFunc("/libreria-b.loans.CalculateLoan", h.options.instrument("libreria-b.loans", "CalculateLoan", h.handlelibreria_b_loans_CalculateLoan))
+	}
+	
+	if h.options.serves("libreria-b.loans") {
+		mux.HandleFunc("/libreria-b.loans.SayHello", h.options.instrument("libreria-b.loans", "SayHello", h.handlelibreria_b_loans_SayHello))
+	}
+	
+
+	catalog := catalogJSON
+	if h.options.namespaces != nil {
+		catalog = discovery.Filter(catalogJSON, h.options.serves)
+	}
+	discovery.Register(mux, catalog)
+}
+
+// ReadinessChecks returns the HealthCheck functions declared by the
+// library domains, by namespace. The server runs them to answer /readyz.
+func ReadinessChecks() map[string]func() error {
+	return map[string]func() error{
+		
+	}
+}
+
+// FieldError is one invalid field of a request. Field is the path in
+// params, e.g. "req.amount" or "req.items[2].currency".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// RequestError rejects a call before it reaches the library. Handlers
+// answer it with 400 Bad Request:
+// {"error": "...", "fields": [{"field": "req.amount", ...}]}.
+type RequestError struct {
+	Fields []FieldError
+}
+
+func (e *RequestError) add(field, rule, message string) {
+	field = strings.TrimPrefix(field, ".") // Fields of a flattened struct
+	e.Fields = append(e.Fields, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+func (e *RequestError) Error() string {
+	var parts []string
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+" "+f.Message)
+	}
+	return "invalid params: " + strings.Join(parts, "; ")
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": reqErr.Error(), "fields": reqErr.Fields})
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// --- Parameter Binding ---
+
+// rawRequest is the body of every route. Params are kept raw so each value
+// is decoded once, straight into the type of its input.
+type rawRequest struct {
+	Params map[string]json.RawMessage `json:"params"`
+}
+
+// paramTable binds the keys of "params" to the inputs of one function. The
+// lookup maps of every mode are built once, at init; -1 marks a key that
+// matches several inputs.
+type paramTable struct {
+	names  []string
+	byMode [3]map[string]int
+}
+
+func newParamTable(names ...string) *paramTable {
+	t := &paramTable{names: names}
+	for mode := range t.byMode {
+		lookup := make(map[string]int)
+		for i, name := range names {
+			for _, key := range matchKeys(MatchMode(mode), name) {
+				if j, dup := lookup[key]; dup && j != i {
+					lookup[key] = -1
+				} else {
+					lookup[key] = i
+				}
+			}
+		}
+		t.byMode[mode] = lookup
+	}
+	return t
+}
+
+// matchKeys lists the keys accepted for an input in a mode. Fuzzy keys are
+// normalized with fuzzyKey before the lookup.
+func matchKeys(mode MatchMode, name string) []string {
+	switch mode {
+	case MatchStrict:
+		return []string{name}
+	case MatchSnakeCamel:
+		keys := []string{name, snakeToCamel(name)}
+		if whole := joinLetters(name); whole != name {
+			keys = append(keys, whole, snakeToCamel(whole))
+		}
+		return keys
+	}
+	return []string{fuzzyKey(name)}
+}
+
+// joinLetters joins the runs of one-letter words that the catalog names
+// get from acronyms: user_i_d -> user_id, h_t_t_p_code -> http_code.
+func joinLetters(name string) string {
+	var words []string
+	run := ""
+	for _, w := range strings.Split(name, "_") {
+		if len([]rune(w)) == 1 {
+			run += w
+			continue
+		}
+		if run != "" {
+			words, run = append(words, run), ""
+		}
+		words = append(words, w)
+	}
+	if run != "" {
+		words = append(words, run)
+	}
+	return strings.Join(words, "_")
+}
+
+func fuzzyKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+func snakeToCamel(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			r := []rune(parts[i])
+			r[0] = unicode.ToUpper(r[0])
+			parts[i] = string(r)
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// bind returns the value of each input, nil when missing. Keys that match
+// no input are ignored; a key matching several inputs, or two keys
+// matching the same input, are a *RequestError.
+// Only args is allocated on success; the errors are built when found.
+func (t *paramTable) bind(params map[string]json.RawMessage, mode MatchMode) ([]json.RawMessage, error) {
+	args := make([]json.RawMessage, len(t.names))
+	var errs *RequestError
+	for k, v := range params {
+		i, ok := t.lookup(k, mode)
+		switch {
+		case !ok:
+			continue
+		case i < 0:
+			if errs == nil {
+				errs = &RequestError{}
+			}
+			errs.add(k, "ambiguous", "matches more than one param")
+		case args[i] != nil:
+			if errs == nil {
+				errs = &RequestError{}
+			}
+			first, second := t.otherKey(params, mode, i, k), k
+			if second < first {
+				first, second = second, first
+			}
+			errs.add(t.names[i], "ambiguous", "given twice, as \""+first+"\" and \""+second+"\"")
+		default:
+			args[i] = v
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	return args, nil
+}
+
+// lookup returns the input a key of params binds to in mode. Fuzzy keys
+// of up to 64 ASCII bytes are normalized on the stack: the map index with
+// string(buf) does not allocate.
+func (t *paramTable) lookup(key string, mode MatchMode) (int, bool) {
+	if mode != MatchFuzzy {
+		i, ok := t.byMode[mode][key]
+		return i, ok
+	}
+	var buf [64]byte
+	n := 0
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= 0x80 || n == len(buf) {
+			i, ok := t.byMode[mode][fuzzyKey(key)]
+			return i, ok
+		}
+		switch {
+		case c == '_':
+		case 'A' <= c && c <= 'Z':
+			buf[n], n = c+'a'-'A', n+1
+		default:
+			buf[n], n = c, n+1
+		}
+	}
+	i, ok := t.byMode[mode][string(buf[:n])]
+	return i, ok
+}
+
+// otherKey returns a key of params, other than key, bound to input i.
+func (t *paramTable) otherKey(params map[string]json.RawMessage, mode MatchMode, i int, key string) string {
+	for k := range params {
+		if j, ok := t.lookup(k, mode); ok && j == i && k != key {
+			return k
+		}
+	}
+	return ""
+}
+
+// flatten collects the struct fields given at the top level of params
+// (args[1:]) into an object for the only input, args[0]. It returns nil
+// when no field was given.
+func (t *paramTable) flatten(args []json.RawMessage) (json.RawMessage, error) {
+	var flat map[string]json.RawMessage
+	var given []string
+	for i := 1; i < len(args); i++ {
+		if args[i] == nil {
+			continue
+		}
+		if flat == nil {
+			flat = make(map[string]json.RawMessage)
+		}
+		flat[t.names[i]] = args[i]
+		given = append(given, t.names[i])
+	}
+	if flat != nil && args[0] != nil {
+		errs := &RequestError{}
+		errs.add(t.names[0], "conflict", "given both nested and as top-level fields ("+strings.Join(given, ", ")+")")
+		return nil, errs
+	}
+	if flat == nil {
+		return nil, nil
+	}
+	return json.Marshal(flat)
+}
+
+// Inputs with an encoding of their own; see decodeParam.
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// decodeParam decodes one value of params into v, leaving v untouched when
+// the value is missing. On top of encoding/json:
+//   - numbers are decoded from their text, so int64 inputs keep every digit,
+//     fractional or out of range values are rejected and interface{}
+//     values get a json.Number;
+//   - numeric inputs, *big.Int, *big.Float and *big.Rat included, also
+//     accept the number as a string: "9007199254740993";
+//   - time.Duration is "1m30s" or an integer of nanoseconds;
+//   - time.Time is an RFC 3339 string and []byte a base64 string.
+// Values that do not decode are added to errs under path.
+func decodeParam(errs *RequestError, path string, raw json.RawMessage, v interface{}) {
+	if raw == nil {
+		return
+	}
+	t := reflect.TypeOf(v).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var err error
+	if raw = normalizeParam(raw, t); holdsInterface(t) {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		err = dec.Decode(v)
+	} else {
+		err = json.Unmarshal(raw, v) // Typed numbers are parsed from their text as well
+	}
+	if err == nil {
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			path += "." + typeErr.Field
+		}
+		errs.add(path, "type", typeMessage(typeErr.Type, typeErr.Value))
+	case errors.As(err, &timeErr):
+		errs.add(path, "type", "must be "+jsonKind(timeType))
+	case t == bigIntType || t == bigFloatType || t == bigRatType:
+		errs.add(path, "type", "must be "+jsonKind(t))
+	default:
+		errs.add(path, "type", err.Error())
+	}
+}
+
+// normalizeParam rewrites the encodings accepted for t that encoding/json
+// does not decode by itself.
+func normalizeParam(raw json.RawMessage, t reflect.Type) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	quoted := raw[0] == '"'
+	switch {
+	case quoted && t == durationType:
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			if d, err := time.ParseDuration(s); err == nil {
+				return json.RawMessage(strconv.FormatInt(int64(d), 10))
+			}
+		}
+	case quoted && (isNumberKind(t.Kind()) || t == bigIntType):
+		if s := raw[1 : len(raw)-1]; isJSONNumber(s) { // A number has no escapes to undo
+			return s
+		}
+	case !quoted && (t == bigFloatType || t == bigRatType) && isJSONNumber(raw):
+		// Both only decode from strings
+		return json.RawMessage(strconv.Quote(string(raw)))
+	}
+	return raw
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isJSONNumber(b []byte) bool {
+	if len(b) == 0 || (b[0] != '-' && (b[0] < '0' || b[0] > '9')) {
+		return false
+	}
+	return json.Valid(b)
+}
+
+// interfaceTypes memoizes holdsInterface: reflect.Type -> bool.
+var interfaceTypes sync.Map
+
+// holdsInterface reports whether a value of t can hold an interface{}, the
+// only case decodeParam needs a json.Decoder for, to get json.Number.
+// Other inputs decode with json.Unmarshal, which allocates no buffer.
+func holdsInterface(t reflect.Type) bool {
+	if holds, ok := interfaceTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	holds := containsInterface(t, make(map[reflect.Type]bool))
+	interfaceTypes.Store(t, holds)
+	return holds
+}
+
+func containsInterface(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return containsInterface(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); (f.IsExported() || f.Anonymous) && containsInterface(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeMessage describes why value, as reported by encoding/json ("string",
+// "number 300"), is not a valid t.
+func typeMessage(t reflect.Type, value string) string {
+	if number, ok := strings.CutPrefix(value, "number "); ok && isNumberKind(t.Kind()) && t != durationType {
+		fractional := strings.ContainsAny(number, ".eE")
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return "must be a number within " + t.Kind().String() + " range, got " + number
+		default:
+			if !fractional {
+				return "must be an integer within " + t.Kind().String() + " range, got " + number
+			}
+		}
+	}
+	if value == "string" && (t == durationType || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8)) {
+		return "must be " + jsonKind(t) // A string, just not a valid one
+	}
+	return "must be " + jsonKind(t) + ", got " + value
+}
+
+// jsonKind names the JSON value expected for t.
+func jsonKind(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "a duration like \"1m30s\" or an integer of nanoseconds"
+	case timeType:
+		return "an RFC 3339 timestamp"
+	case bigIntType:
+		return "an integer"
+	case bigFloatType, bigRatType:
+		return "a number"
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "a base64 string"
+		}
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return t.String()
+}
+
+
+var paramslibreria_a_system_GetSystemStatus = newParamTable("code")
+
+// requestlibreria_a_system_GetSystemStatus holds the decoded inputs of GetSystemStatus.
+type requestlibreria_a_system_GetSystemStatus struct {
+	code string
 	
-	mux.HandleFunc("/libreria-a.transfers.national.GetUserBalance", handlelibreria_a_transfers_national_GetUserBalance)
+}
+
+var paramslibreria_a_transfers_national_GetUserBalance = newParamTable("user_i_d", "account_i_d")
+
+// requestlibreria_a_transfers_national_GetUserBalance holds the decoded inputs of GetUserBalance.
+type requestlibreria_a_transfers_national_GetUserBalance struct {
+	user_i_d string
+	account_i_d string
 	
-	mux.HandleFunc("/libreria-a.transfers.national.Transfer", handlelibreria_a_transfers_national_Transfer)
+}
+
+var paramslibreria_a_transfers_national_Transfer = newParamTable("source_account", "dest_account", "amount", "currency")
+
+// requestlibreria_a_transfers_national_Transfer holds the decoded inputs of Transfer.
+type requestlibreria_a_transfers_national_Transfer struct {
+	source_account string
+	dest_account string
+	amount float64
+	currency string
 	
-	mux.HandleFunc("/libreria-a.transfers.national.ComplexTransfer", handlelibreria_a_transfers_national_ComplexTransfer)
+}
+
+var paramslibreria_a_transfers_national_ComplexTransfer = newParamTable("req", "source_account", "dest_account", "amount", "currency")
+
+// requestlibreria_a_transfers_national_ComplexTransfer holds the decoded inputs of ComplexTransfer.
+type requestlibreria_a_transfers_national_ComplexTransfer struct {
+	req libreria_a_transfers_national.TransferRequest
 	
-	mux.HandleFunc("/libreria-a.transfers.international.InternationalTransfer", handlelibreria_a_transfers_international_InternationalTransfer)
+}
+
+var paramslibreria_a_transfers_international_InternationalTransfer = newParamTable("source_account", "dest_iban", "amount", "swift_code")
+
+// requestlibreria_a_transfers_international_InternationalTransfer holds the decoded inputs of InternationalTransfer.
+type requestlibreria_a_transfers_international_InternationalTransfer struct {
+	source_account string
+	dest_iban string
+	amount float64
+	swift_code string
 	
-	mux.HandleFunc("/libreria-b.loans.CalculateLoan", handlelibreria_b_loans_CalculateLoan)
+}
+
+var paramslibreria_b_loans_CalculateLoan = newParamTable("req", "amount", "term", "user_type")
+
+// requestlibreria_b_loans_CalculateLoan holds the decoded inputs of CalculateLoan.
+type requestlibreria_b_loans_CalculateLoan struct {
+	req libreria_b_loans.LoanRequest
 	
-	mux.HandleFunc("/libreria-b.loans.SayHello", handlelibreria_b_loans_SayHello)
+}
+
+var paramslibreria_b_loans_SayHello = newParamTable("msn")
+
+// requestlibreria_b_loans_SayHello holds the decoded inputs of SayHello.
+type requestlibreria_b_loans_SayHello struct {
+	msn string
 	
 }
 
 
-func handlelibreria_a_system_GetSystemStatus(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+// --- Validation ---
+
+
+
+
+func (h *handlers) handlelibreria_a_system_GetSystemStatus(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -46,56 +680,47 @@ func handlelibreria_a_system_GetSystemStatus(w http.ResponseWriter, r *http.Requ
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_a_system_GetSystemStatus(params)
+	resp, err := wrapperlibreria_a_system_GetSystemStatus(params, h.options.matchModeFor("libreria-a.system.GetSystemStatus"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_a_system_GetSystemStatus(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_a_system_GetSystemStatus(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: code(string)
     
     
     
+    args, bindErr := paramslibreria_a_system_GetSystemStatus.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
+    }
     
-    // Determine Type string (Primitive vs Complex)
     
-    var val_code string
     
-
-    // Fuzzy Match Logic
-    found_code := false
-    target_code := strings.ToLower(strings.ReplaceAll("code", "_", ""))
+    // Decode
+    var in requestlibreria_a_system_GetSystemStatus
+    errs := &RequestError{}
+    decodeParam(errs, "code", args[0], &in.code)
     
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_code {
-            
-            val_code, _ = v.(string)
-            
-            found_code = true
-            break
-        }
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
-    if !found_code {
-       // Optional: Log or Error if required param missing?
-    }
     
 
     // Call
     
         
             // Expected: (val, error)
-            ret0, err := libreria_a_system.GetSystemStatus(val_code)
+            ret0, err := libreria_a_system.GetSystemStatus(in.code)
             if err != nil {
                 return nil, err
             }
@@ -104,9 +729,17 @@ func wrapperlibreria_a_system_GetSystemStatus(params map[string]interface{}) (in
     
 }
 
-func handlelibreria_a_transfers_national_GetUserBalance(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handlelibreria_a_transfers_national_GetUserBalance(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -115,81 +748,48 @@ func handlelibreria_a_transfers_national_GetUserBalance(w http.ResponseWriter, r
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_a_transfers_national_GetUserBalance(params)
+	resp, err := wrapperlibreria_a_transfers_national_GetUserBalance(params, h.options.matchModeFor("libreria-a.transfers.national.GetUserBalance"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_a_transfers_national_GetUserBalance(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_a_transfers_national_GetUserBalance(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: user_i_d(string), account_i_d(string)
     
     
     
-    
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_user_i_d string
-    
-
-    // Fuzzy Match Logic
-    found_user_i_d := false
-    target_user_i_d := strings.ToLower(strings.ReplaceAll("user_i_d", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_user_i_d {
-            
-            val_user_i_d, _ = v.(string)
-            
-            found_user_i_d = true
-            break
-        }
-    }
-    
-    if !found_user_i_d {
-       // Optional: Log or Error if required param missing?
+    args, bindErr := paramslibreria_a_transfers_national_GetUserBalance.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
     }
     
     
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_account_i_d string
     
-
-    // Fuzzy Match Logic
-    found_account_i_d := false
-    target_account_i_d := strings.ToLower(strings.ReplaceAll("account_i_d", "_", ""))
+    // Decode
+    var in requestlibreria_a_transfers_national_GetUserBalance
+    errs := &RequestError{}
+    decodeParam(errs, "user_i_d", args[0], &in.user_i_d)
+    decodeParam(errs, "account_i_d", args[1], &in.account_i_d)
     
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_account_i_d {
-            
-            val_account_i_d, _ = v.(string)
-            
-            found_account_i_d = true
-            break
-        }
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
-    if !found_account_i_d {
-       // Optional: Log or Error if required param missing?
-    }
     
 
     // Call
     
         
             // Expected: (val, error)
-            ret0, err := libreria_a_transfers_national.GetUserBalance(val_user_i_d, val_account_i_d)
+            ret0, err := libreria_a_transfers_national.GetUserBalance(in.user_i_d, in.account_i_d)
             if err != nil {
                 return nil, err
             }
@@ -198,9 +798,17 @@ func wrapperlibreria_a_transfers_national_GetUserBalance(params map[string]inter
     
 }
 
-func handlelibreria_a_transfers_national_Transfer(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handlelibreria_a_transfers_national_Transfer(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -209,131 +817,50 @@ func handlelibreria_a_transfers_national_Transfer(w http.ResponseWriter, r *http
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_a_transfers_national_Transfer(params)
+	resp, err := wrapperlibreria_a_transfers_national_Transfer(params, h.options.matchModeFor("libreria-a.transfers.national.Transfer"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_a_transfers_national_Transfer(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_a_transfers_national_Transfer(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: source_account(string), dest_account(string), amount(float64), currency(string)
     
     
     
-    
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_source_account string
-    
-
-    // Fuzzy Match Logic
-    found_source_account := false
-    target_source_account := strings.ToLower(strings.ReplaceAll("source_account", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_source_account {
-            
-            val_source_account, _ = v.(string)
-            
-            found_source_account = true
-            break
-        }
+    args, bindErr := paramslibreria_a_transfers_national_Transfer.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
     }
     
-    if !found_source_account {
-       // Optional: Log or Error if required param missing?
-    }
     
     
-    // Determine Type string (Primitive vs Complex)
+    // Decode
+    var in requestlibreria_a_transfers_national_Transfer
+    errs := &RequestError{}
+    decodeParam(errs, "source_account", args[0], &in.source_account)
+    decodeParam(errs, "dest_account", args[1], &in.dest_account)
+    decodeParam(errs, "amount", args[2], &in.amount)
+    decodeParam(errs, "currency", args[3], &in.currency)
     
-    var val_dest_account string
-    
-
-    // Fuzzy Match Logic
-    found_dest_account := false
-    target_dest_account := strings.ToLower(strings.ReplaceAll("dest_account", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_dest_account {
-            
-            val_dest_account, _ = v.(string)
-            
-            found_dest_account = true
-            break
-        }
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
-    if !found_dest_account {
-       // Optional: Log or Error if required param missing?
-    }
-    
-    
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_amount float64
-    
-
-    // Fuzzy Match Logic
-    found_amount := false
-    target_amount := strings.ToLower(strings.ReplaceAll("amount", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_amount {
-            
-            val_amount, _ = v.(float64)
-            
-            found_amount = true
-            break
-        }
-    }
-    
-    if !found_amount {
-       // Optional: Log or Error if required param missing?
-    }
-    
-    
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_currency string
-    
-
-    // Fuzzy Match Logic
-    found_currency := false
-    target_currency := strings.ToLower(strings.ReplaceAll("currency", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_currency {
-            
-            val_currency, _ = v.(string)
-            
-            found_currency = true
-            break
-        }
-    }
-    
-    if !found_currency {
-       // Optional: Log or Error if required param missing?
-    }
     
 
     // Call
     
         
             // Expected: (val, error)
-            ret0, err := libreria_a_transfers_national.Transfer(val_source_account, val_dest_account, val_amount, val_currency)
+            ret0, err := libreria_a_transfers_national.Transfer(in.source_account, in.dest_account, in.amount, in.currency)
             if err != nil {
                 return nil, err
             }
@@ -342,9 +869,17 @@ func wrapperlibreria_a_transfers_national_Transfer(params map[string]interface{}
     
 }
 
-func handlelibreria_a_transfers_national_ComplexTransfer(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handlelibreria_a_transfers_national_ComplexTransfer(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -353,58 +888,57 @@ func handlelibreria_a_transfers_national_ComplexTransfer(w http.ResponseWriter,
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_a_transfers_national_ComplexTransfer(params)
+	resp, err := wrapperlibreria_a_transfers_national_ComplexTransfer(params, h.options.matchModeFor("libreria-a.transfers.national.ComplexTransfer"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_a_transfers_national_ComplexTransfer(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_a_transfers_national_ComplexTransfer(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: req(TransferRequest)
     
     
     
+    args, bindErr := paramslibreria_a_transfers_national_ComplexTransfer.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
+    }
     
-    // Determine Type string (Primitive vs Complex)
     
-    var val_req libreria_a_transfers_national.TransferRequest
+    // Flattened: the fields of req may also be given at the top level of params
+    flat, flatErr := paramslibreria_a_transfers_national_ComplexTransfer.flatten(args)
+    if flatErr != nil {
+        return nil, flatErr
+    }
+    path := "req"
+    if flat != nil {
+        args[0], path = flat, "" // Fields are reported without the param name
+    }
     
-
-    // Fuzzy Match Logic
-    found_req := false
-    target_req := strings.ToLower(strings.ReplaceAll("req", "_", ""))
     
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_req {
-            
-            // Complex Type: Convert map -> json -> struct
-            jsonBody, _ := json.Marshal(v)
-            json.Unmarshal(jsonBody, &val_req)
-            
-            found_req = true
-            break
-        }
-    }
+    // Decode
+    var in requestlibreria_a_transfers_national_ComplexTransfer
+    errs := &RequestError{}
+    decodeParam(errs, path, args[0], &in.req)
     
-    if !found_req {
-       // Optional: Log or Error if required param missing?
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
+    
 
     // Call
     
         
             // Expected: (val, error)
-            ret0, err := libreria_a_transfers_national.ComplexTransfer(val_req)
+            ret0, err := libreria_a_transfers_national.ComplexTransfer(in.req)
             if err != nil {
                 return nil, err
             }
@@ -413,9 +947,17 @@ func wrapperlibreria_a_transfers_national_ComplexTransfer(params map[string]inte
     
 }
 
-func handlelibreria_a_transfers_international_InternationalTransfer(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handlelibreria_a_transfers_international_InternationalTransfer(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -424,131 +966,50 @@ func handlelibreria_a_transfers_international_InternationalTransfer(w http.Respo
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_a_transfers_international_InternationalTransfer(params)
+	resp, err := wrapperlibreria_a_transfers_international_InternationalTransfer(params, h.options.matchModeFor("libreria-a.transfers.international.InternationalTransfer"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_a_transfers_international_InternationalTransfer(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_a_transfers_international_InternationalTransfer(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: source_account(string), dest_iban(string), amount(float64), swift_code(string)
     
     
     
-    
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_source_account string
-    
-
-    // Fuzzy Match Logic
-    found_source_account := false
-    target_source_account := strings.ToLower(strings.ReplaceAll("source_account", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_source_account {
-            
-            val_source_account, _ = v.(string)
-            
-            found_source_account = true
-            break
-        }
-    }
-    
-    if !found_source_account {
-       // Optional: Log or Error if required param missing?
+    args, bindErr := paramslibreria_a_transfers_international_InternationalTransfer.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
     }
     
     
-    // Determine Type string (Primitive vs Complex)
     
-    var val_dest_iban string
-    
-
-    // Fuzzy Match Logic
-    found_dest_iban := false
-    target_dest_iban := strings.ToLower(strings.ReplaceAll("dest_iban", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_dest_iban {
-            
-            val_dest_iban, _ = v.(string)
-            
-            found_dest_iban = true
-            break
-        }
-    }
+    // Decode
+    var in requestlibreria_a_transfers_international_InternationalTransfer
+    errs := &RequestError{}
+    decodeParam(errs, "source_account", args[0], &in.source_account)
+    decodeParam(errs, "dest_iban", args[1], &in.dest_iban)
+    decodeParam(errs, "amount", args[2], &in.amount)
+    decodeParam(errs, "swift_code", args[3], &in.swift_code)
     
-    if !found_dest_iban {
-       // Optional: Log or Error if required param missing?
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
     
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_amount float64
-    
-
-    // Fuzzy Match Logic
-    found_amount := false
-    target_amount := strings.ToLower(strings.ReplaceAll("amount", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_amount {
-            
-            val_amount, _ = v.(float64)
-            
-            found_amount = true
-            break
-        }
-    }
-    
-    if !found_amount {
-       // Optional: Log or Error if required param missing?
-    }
-    
-    
-    // Determine Type string (Primitive vs Complex)
-    
-    var val_swift_code string
-    
-
-    // Fuzzy Match Logic
-    found_swift_code := false
-    target_swift_code := strings.ToLower(strings.ReplaceAll("swift_code", "_", ""))
-    
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_swift_code {
-            
-            val_swift_code, _ = v.(string)
-            
-            found_swift_code = true
-            break
-        }
-    }
-    
-    if !found_swift_code {
-       // Optional: Log or Error if required param missing?
-    }
-    
 
     // Call
     
         
             // Expected: (val, error)
-            ret0, err := libreria_a_transfers_international.InternationalTransfer(val_source_account, val_dest_iban, val_amount, val_swift_code)
+            ret0, err := libreria_a_transfers_international.InternationalTransfer(in.source_account, in.dest_iban, in.amount, in.swift_code)
             if err != nil {
                 return nil, err
             }
@@ -557,9 +1018,17 @@ func wrapperlibreria_a_transfers_international_InternationalTransfer(params map[
     
 }
 
-func handlelibreria_b_loans_CalculateLoan(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handlelibreria_b_loans_CalculateLoan(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -568,58 +1037,57 @@ func handlelibreria_b_loans_CalculateLoan(w http.ResponseWriter, r *http.Request
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_b_loans_CalculateLoan(params)
+	resp, err := wrapperlibreria_b_loans_CalculateLoan(params, h.options.matchModeFor("libreria-b.loans.CalculateLoan"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_b_loans_CalculateLoan(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_b_loans_CalculateLoan(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: req(LoanRequest)
     
     
     
+    args, bindErr := paramslibreria_b_loans_CalculateLoan.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
+    }
     
-    // Determine Type string (Primitive vs Complex)
     
-    var val_req libreria_b_loans.LoanRequest
+    // Flattened: the fields of req may also be given at the top level of params
+    flat, flatErr := paramslibreria_b_loans_CalculateLoan.flatten(args)
+    if flatErr != nil {
+        return nil, flatErr
+    }
+    path := "req"
+    if flat != nil {
+        args[0], path = flat, "" // Fields are reported without the param name
+    }
     
-
-    // Fuzzy Match Logic
-    found_req := false
-    target_req := strings.ToLower(strings.ReplaceAll("req", "_", ""))
     
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_req {
-            
-            // Complex Type: Convert map -> json -> struct
-            jsonBody, _ := json.Marshal(v)
-            json.Unmarshal(jsonBody, &val_req)
-            
-            found_req = true
-            break
-        }
-    }
+    // Decode
+    var in requestlibreria_b_loans_CalculateLoan
+    errs := &RequestError{}
+    decodeParam(errs, path, args[0], &in.req)
     
-    if !found_req {
-       // Optional: Log or Error if required param missing?
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
+    
 
     // Call
     
         
             // Expected: (val, error)
-            ret0, err := libreria_b_loans.CalculateLoan(val_req)
+            ret0, err := libreria_b_loans.CalculateLoan(in.req)
             if err != nil {
                 return nil, err
             }
@@ -628,9 +1096,17 @@ func wrapperlibreria_b_loans_CalculateLoan(params map[string]interface{}) (inter
     
 }
 
-func handlelibreria_b_loans_SayHello(w http.ResponseWriter, r *http.Request) {
-	var req GenericRequest
+func (h *handlers) handlelibreria_b_loans_SayHello(w http.ResponseWriter, r *http.Request) {
+	if h.options.maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.maxBody)
+	}
+	var req rawRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -639,49 +1115,40 @@ func handlelibreria_b_loans_SayHello(w http.ResponseWriter, r *http.Request) {
 	params := req.Params
 	
 	// 2. Call Implementation
-	resp, err := wrapperlibreria_b_loans_SayHello(params)
+	resp, err := wrapperlibreria_b_loans_SayHello(params, h.options.matchModeFor("libreria-b.loans.SayHello"))
 	
 	// 3. Response
 	w.Header().Set("Content-Type", "application/json")
-	
 	if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-        return
+		writeError(w, err)
+		return
 	}
+	
 	json.NewEncoder(w).Encode(resp)
 	
 }
 
-func wrapperlibreria_b_loans_SayHello(params map[string]interface{}) (interface{}, error) {
+func wrapperlibreria_b_loans_SayHello(params map[string]json.RawMessage, mode MatchMode) (interface{}, error) {
     // Inputs: msn(string)
     
     
     
+    args, bindErr := paramslibreria_b_loans_SayHello.bind(params, mode)
+    if bindErr != nil {
+        return nil, bindErr
+    }
     
-    // Determine Type string (Primitive vs Complex)
     
-    var val_msn string
     
-
-    // Fuzzy Match Logic
-    found_msn := false
-    target_msn := strings.ToLower(strings.ReplaceAll("msn", "_", ""))
+    // Decode
+    var in requestlibreria_b_loans_SayHello
+    errs := &RequestError{}
+    decodeParam(errs, "msn", args[0], &in.msn)
     
-    for k, v := range params {
-        normalizedK := strings.ToLower(strings.ReplaceAll(k, "_", ""))
-        if normalizedK == target_msn {
-            
-            val_msn, _ = v.(string)
-            
-            found_msn = true
-            break
-        }
+    if len(errs.Fields) > 0 {
+        return nil, errs
     }
     
-    if !found_msn {
-       // Optional: Log or Error if required param missing?
-    }
     
 
     // Call
@@ -689,7 +1156,7 @@ func wrapperlibreria_b_loans_SayHello(params map[string]interface{}) (interface{
         // No error returned
         
             // Expected: (val)
-            ret0 := libreria_b_loans.SayHello(val_msn)
+            ret0 := libreria_b_loans.SayHello(in.msn)
             return ret0, nil
         
     
@@ -0,0 +1,189 @@
+package generated
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Method names as used by Transport.Call.
+const (
+	MethodLibreriaaSystemGetSystemStatus = "libreria-a.system.GetSystemStatus"
+	MethodLibreriaaTransfersNationalGetUserBalance = "libreria-a.transfers.national.GetUserBalance"
+	MethodLibreriaaTransfersNationalTransfer = "libreria-a.transfers.national.Transfer"
+	MethodLibreriaaTransfersNationalComplexTransfer = "libreria-a.transfers.national.ComplexTransfer"
+	MethodLibreriaaTransfersInternationalInternationalTransfer = "libreria-a.transfers.international.InternationalTransfer"
+	MethodLibreriabLoansCalculateLoan = "libreria-b.loans.CalculateLoan"
+	MethodLibreriabLoansSayHello = "libreria-b.loans.SayHello"
+)
+
+// --- In-Process Transport ---
+
+// InProcessTransport calls the server wrappers directly, without HTTP.
+// Params and results go through JSON so callers see the same shapes
+// (float64 numbers, map[string]interface{} structs) as with NewClient, and
+// params are bound and decoded exactly as by the HTTP server. Of the
+// Options, only the match modes apply.
+type InProcessTransport struct {
+	options serverOptions
+}
+
+func NewInProcessTransport(opts ...Option) *InProcessTransport {
+	t := &InProcessTransport{}
+	for _, opt := range opts {
+		opt(&t.options)
+	}
+	return t
+}
+
+// NewInProcessClient returns a Client backed by an InProcessTransport.
+func NewInProcessClient() *Client {
+	return NewClientWithTransport(NewInProcessTransport())
+}
+
+var inProcessWrappers = map[string]func(params map[string]json.RawMessage, mode MatchMode) (interface{}, error){
+	MethodLibreriaaSystemGetSystemStatus: wrapperlibreria_a_system_GetSystemStatus,
+	MethodLibreriaaTransfersNationalGetUserBalance: wrapperlibreria_a_transfers_national_GetUserBalance,
+	MethodLibreriaaTransfersNationalTransfer: wrapperlibreria_a_transfers_national_Transfer,
+	MethodLibreriaaTransfersNationalComplexTransfer: wrapperlibreria_a_transfers_national_ComplexTransfer,
+	MethodLibreriaaTransfersInternationalInternationalTransfer: wrapperlibreria_a_transfers_international_InternationalTransfer,
+	MethodLibreriabLoansCalculateLoan: wrapperlibreria_b_loans_CalculateLoan,
+	MethodLibreriabLoansSayHello: wrapperlibreria_b_loans_SayHello,
+}
+
+func (t *InProcessTransport) Call(method string, req GenericRequest) (interface{}, error) {
+	wrapper, ok := inProcessWrappers[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown method: %s", method)
+	}
+
+	var params map[string]json.RawMessage
+	if err := roundTripJSON(req.Params, &params); err != nil {
+		return nil, fmt.Errorf("invalid params: %v", err)
+	}
+
+	resp, err := wrapper(params, t.options.matchModeFor(method))
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	if err := roundTripJSON(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func roundTripJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// --- Mock Transport ---
+
+// MockHandler answers a call made through a MockTransport.
+type MockHandler func(params map[string]interface{}) (interface{}, error)
+
+// MockCall records one call made through a MockTransport.
+type MockCall struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// MockTransport is a programmable Transport for unit tests. Methods without
+// a stub fail with an error; every call is recorded, stubbed or not.
+type MockTransport struct {
+	mu    sync.Mutex
+	stubs map[string]MockHandler
+	calls []MockCall
+}
+
+func NewMockTransport() *MockTransport {
+	return &MockTransport{stubs: make(map[string]MockHandler)}
+}
+
+// On stubs a method by name (see the Method* constants).
+func (m *MockTransport) On(method string, handler MockHandler) *MockTransport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs[method] = handler
+	return m
+}
+
+// Return stubs a method with a fixed result and error.
+func (m *MockTransport) Return(method string, result interface{}, err error) *MockTransport {
+	return m.On(method, func(map[string]interface{}) (interface{}, error) {
+		return result, err
+	})
+}
+
+func (m *MockTransport) Call(method string, req GenericRequest) (interface{}, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, MockCall{Method: method, Params: req.Params})
+	handler, ok := m.stubs[method]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("mock: no stub for method %s", method)
+	}
+	return handler(req.Params)
+}
+
+// Calls returns every recorded call in order.
+func (m *MockTransport) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls to one method.
+func (m *MockTransport) CallsTo(method string) []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []MockCall
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset drops all stubs and recorded calls.
+func (m *MockTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs = make(map[string]MockHandler)
+	m.calls = nil
+}
+
+func (m *MockTransport) OnLibreriaaSystemGetSystemStatus(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriaaSystemGetSystemStatus, handler)
+}
+
+func (m *MockTransport) OnLibreriaaTransfersNationalGetUserBalance(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriaaTransfersNationalGetUserBalance, handler)
+}
+
+func (m *MockTransport) OnLibreriaaTransfersNationalTransfer(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriaaTransfersNationalTransfer, handler)
+}
+
+func (m *MockTransport) OnLibreriaaTransfersNationalComplexTransfer(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriaaTransfersNationalComplexTransfer, handler)
+}
+
+func (m *MockTransport) OnLibreriaaTransfersInternationalInternationalTransfer(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriaaTransfersInternationalInternationalTransfer, handler)
+}
+
+func (m *MockTransport) OnLibreriabLoansCalculateLoan(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriabLoansCalculateLoan, handler)
+}
+
+func (m *MockTransport) OnLibreriabLoansSayHello(handler MockHandler) *MockTransport {
+	return m.On(MethodLibreriabLoansSayHello, handler)
+}
@@ -0,0 +1,54 @@
+//go:build nexus_grpc
+
+package main
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"github.com/japablazatww/nexus/nexus/config"
+	"github.com/japablazatww/nexus/nexus/generated"
+)
+
+func init() {
+	extraServers = append(extraServers, serveGRPC)
+}
+
+// serveGRPC exposes the services from generated/nexus.proto, with the
+// options of the HTTP server: namespace filter, body limit and metrics.
+// Requires the generated code to be built with `nexus-cli build --proto`.
+func serveGRPC(cfg config.Config, options []generated.Option) func(ctx context.Context) {
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		logf(levelError, "Error starting gRPC server: %v", err)
+		return nil
+	}
+	s := grpc.NewServer(generated.GRPCServerOptions(options...)...)
+	generated.RegisterGRPCServices(s, options...)
+
+	logf(levelInfo, "gRPC server listening on %s", cfg.GRPCAddr)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			logf(levelError, "Error serving gRPC: %v", err)
+		}
+	}()
+
+	// GracefulStop waits for every call; Stop cancels the ones left when
+	// the grace period expires.
+	return func(ctx context.Context) {
+		done := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			logf(levelWarn, "gRPC grace period expired, cancelling remaining calls")
+			s.Stop()
+			<-done
+		}
+	}
+}
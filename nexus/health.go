@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// readinessTimeout bounds every /readyz check; a slow check counts as failed.
+const readinessTimeout = 5 * time.Second
+
+// health serves /livez and /readyz. The process is live while it can
+// answer; it is ready while it is not shutting down and every check passes.
+type health struct {
+	checks   map[string]func() error // Name -> check
+	draining atomic.Bool
+}
+
+func newHealth(checks map[string]func() error) *health {
+	return &health{checks: checks}
+}
+
+func (h *health) register(mux *http.ServeMux) {
+	mux.HandleFunc("/livez", h.handleLive)
+	mux.HandleFunc("/health", h.handleLive) // Kept for existing probes
+	mux.HandleFunc("/readyz", h.handleReady)
+}
+
+func (h *health) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// readyStatus is the body of /readyz.
+type readyStatus struct {
+	Status string            `json:"status"` // ready, unavailable or draining
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func (h *health) handleReady(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.draining.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(readyStatus{Status: "draining"})
+		return
+	}
+
+	res := readyStatus{Status: "ready", Checks: h.run()}
+	status := http.StatusOK
+	for _, msg := range res.Checks {
+		if msg != "ok" {
+			res.Status, status = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// run executes the checks concurrently and returns "ok" or the error of
+// each one.
+func (h *health) run() map[string]string {
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(h.checks))
+	for name, check := range h.checks {
+		go func() {
+			results <- result{name, check()}
+		}()
+	}
+
+	out := make(map[string]string, len(h.checks))
+	timeout := time.After(readinessTimeout)
+	for range h.checks {
+		select {
+		case res := <-results:
+			out[res.name] = "ok"
+			if res.err != nil {
+				out[res.name] = res.err.Error()
+			}
+		case <-timeout:
+			for name := range h.checks {
+				if _, done := out[name]; !done {
+					out[name] = "timed out after " + readinessTimeout.String()
+				}
+			}
+			return out
+		}
+	}
+	return out
+}
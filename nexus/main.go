@@ -1,27 +1,124 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/japablazatww/nexus/nexus/config"
 	"github.com/japablazatww/nexus/nexus/generated"
+	"github.com/japablazatww/nexus/nexus/metrics"
 )
 
+// extraServers are started next to the HTTP server. Optional transports
+// built behind tags (see grpc.go) register themselves here from init. Each
+// one serves with the options of the HTTP handlers, so they share the
+// namespace filter, the body limit and the metrics recorder, and
+// returns a function that stops it, waiting for in-flight calls until
+// ctx is done; nil when it did not start.
+var extraServers []func(cfg config.Config, options []generated.Option) (stop func(ctx context.Context))
+
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		os.Exit(2)
+	}
+	setLogLevel(cfg.LogLevel)
+
 	mux := http.NewServeMux()
 
 	// Register generated handlers
-	generated.RegisterHandlers(mux)
+	recorder := metrics.New()
+	options := []generated.Option{
+		generated.WithMaxBodyBytes(cfg.MaxBodyBytes),
+		generated.WithNamespaceFilter(cfg.Enabled),
+		generated.WithMetrics(recorder),
+	}
+	generated.RegisterHandlers(mux, options...)
+	mux.Handle("GET /metrics", recorder)
+
+	// Liveness and readiness, with the HealthCheck hooks of the served domains
+	checks := make(map[string]func() error)
+	for namespace, check := range generated.ReadinessChecks() {
+		if cfg.Enabled(namespace) {
+			checks[namespace] = check
+		}
+	}
+	probes := newHealth(checks)
+	probes.register(mux)
+
+	var stops []func(ctx context.Context)
+	for _, start := range extraServers {
+		if stop := start(cfg, options); stop != nil {
+			stops = append(stops, stop)
+		}
+	}
+
+	srv := &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      withCORS(cfg.CORSOrigins, logRequests(mux)),
+		ReadTimeout:  time.Duration(cfg.ReadTimeout),
+		WriteTimeout: time.Duration(cfg.WriteTimeout),
+		IdleTimeout:  time.Duration(cfg.IdleTimeout),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Health check
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	serveErr := make(chan error, 1)
+	go func() {
+		if cfg.TLSCert != "" {
+			logf(levelInfo, "Server listening on %s (TLS)", cfg.Addr)
+			serveErr <- srv.ListenAndServeTLS(cfg.TLSCert, cfg.TLSKey)
+		} else {
+			logf(levelInfo, "Server listening on %s", cfg.Addr)
+			serveErr <- srv.ListenAndServe()
+		}
+	}()
 
-	port := ":8080"
-	fmt.Printf("[Nexus] Server listening on %s\n", port)
-	if err := http.ListenAndServe(port, mux); err != nil {
-		fmt.Printf("Error starting server: %v\n", err)
+	select {
+	case err := <-serveErr:
+		logf(levelError, "Error starting server: %v", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	stop() // A second signal kills the process
+
+	// Drain: /readyz fails first, so load balancers stop routing here while
+	// the listeners are still open; then the listeners close and in-flight
+	// requests get the grace period to finish.
+	probes.draining.Store(true)
+	if delay := time.Duration(cfg.ShutdownDrainDelay); delay > 0 {
+		logf(levelInfo, "Shutting down, waiting %s for load balancers to stop routing", delay)
+		time.Sleep(delay)
+	}
+	grace := time.Duration(cfg.ShutdownGrace)
+	logf(levelInfo, "Draining requests for up to %s", grace)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, stop := range stops {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stop(shutdownCtx)
+		}()
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logf(levelWarn, "Grace period expired, closing remaining connections")
+		srv.Close()
 	}
+	wg.Wait()
+	logf(levelInfo, "Server stopped")
 }
@@ -0,0 +1,204 @@
+// Package metrics records the requests served by the generated routes and
+// exposes them in the Prometheus text exposition format, without external
+// dependencies:
+//
+//	nexus_requests_total{namespace,method}                counter
+//	nexus_request_errors_total{namespace,method,status}   counter, status >= 400
+//	nexus_request_duration_seconds{namespace,method}      histogram
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Recorder holds the metrics of every service. It is safe for concurrent
+// use and serves them over HTTP.
+type Recorder struct {
+	buckets  []float64
+	mu       sync.RWMutex
+	services map[service]*stats
+}
+
+type service struct {
+	namespace string
+	method    string
+}
+
+type stats struct {
+	mu       sync.Mutex
+	requests uint64
+	errors   map[int]uint64 // Status -> count
+	counts   []uint64       // Per bucket, not cumulative; the last one is +Inf
+	sum      float64
+}
+
+// New returns a Recorder with the given histogram buckets, DefaultBuckets
+// when none are given.
+func New(buckets ...float64) *Recorder {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Recorder{buckets: buckets, services: make(map[service]*stats)}
+}
+
+// Register makes a service appear, with zero values, before its first
+// request.
+func (r *Recorder) Register(namespace string, method string) {
+	r.stats(service{namespace, method})
+}
+
+func (r *Recorder) stats(key service) *stats {
+	r.mu.RLock()
+	s, ok := r.services[key]
+	r.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok = r.services[key]; !ok {
+		s = &stats{errors: make(map[int]uint64), counts: make([]uint64, len(r.buckets)+1)}
+		r.services[key] = s
+	}
+	return s
+}
+
+// Observe records one request.
+func (r *Recorder) Observe(namespace string, method string, status int, elapsed time.Duration) {
+	s := r.stats(service{namespace, method})
+	seconds := elapsed.Seconds()
+	bucket := sort.SearchFloat64s(r.buckets, seconds) // First bound >= seconds
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if status >= 400 {
+		s.errors[status]++
+	}
+	s.counts[bucket]++
+	s.sum += seconds
+}
+
+// Instrument returns h recording every request it serves.
+func (r *Recorder) Instrument(namespace string, method string, h http.HandlerFunc) http.HandlerFunc {
+	r.Register(namespace, method)
+	return func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h(rec, req)
+		r.Observe(namespace, method, rec.status, time.Since(start))
+	}
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// ServeHTTP writes every metric in the text exposition format.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// snapshot is a copy of the stats of one service, taken under its lock.
+type snapshot struct {
+	service
+	labels   string
+	requests uint64
+	errors   map[int]uint64
+	counts   []uint64
+	sum      float64
+}
+
+// WriteTo writes every metric in the text exposition format, sorted by
+// namespace and method.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	snaps := make([]snapshot, 0, len(r.services))
+	for key, s := range r.services {
+		s.mu.Lock()
+		snap := snapshot{service: key, requests: s.requests, counts: append([]uint64(nil), s.counts...), sum: s.sum, errors: make(map[int]uint64, len(s.errors))}
+		for status, n := range s.errors {
+			snap.errors[status] = n
+		}
+		s.mu.Unlock()
+		snap.labels = "namespace=" + quote(key.namespace) + ",method=" + quote(key.method)
+		snaps = append(snaps, snap)
+	}
+	r.mu.RUnlock()
+	sort.Slice(snaps, func(i, j int) bool {
+		if snaps[i].namespace != snaps[j].namespace {
+			return snaps[i].namespace < snaps[j].namespace
+		}
+		return snaps[i].method < snaps[j].method
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP nexus_requests_total Requests handled by each service.\n")
+	b.WriteString("# TYPE nexus_requests_total counter\n")
+	for _, s := range snaps {
+		fmt.Fprintf(&b, "nexus_requests_total{%s} %d\n", s.labels, s.requests)
+	}
+
+	b.WriteString("# HELP nexus_request_errors_total Requests answered with a status >= 400, by service and status.\n")
+	b.WriteString("# TYPE nexus_request_errors_total counter\n")
+	for _, s := range snaps {
+		statuses := make([]int, 0, len(s.errors))
+		for status := range s.errors {
+			statuses = append(statuses, status)
+		}
+		sort.Ints(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(&b, "nexus_request_errors_total{%s,status=\"%d\"} %d\n", s.labels, status, s.errors[status])
+		}
+	}
+
+	b.WriteString("# HELP nexus_request_duration_seconds Time to handle a request, by service.\n")
+	b.WriteString("# TYPE nexus_request_duration_seconds histogram\n")
+	for _, s := range snaps {
+		var cumulative uint64
+		for i, bound := range r.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(&b, "nexus_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", s.labels, formatFloat(bound), cumulative)
+		}
+		cumulative += s.counts[len(r.buckets)]
+		fmt.Fprintf(&b, "nexus_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", s.labels, cumulative)
+		fmt.Fprintf(&b, "nexus_request_duration_seconds_sum{%s} %s\n", s.labels, formatFloat(s.sum))
+		fmt.Fprintf(&b, "nexus_request_duration_seconds_count{%s} %d\n", s.labels, cumulative)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// quote escapes a label value: backslash, double quote and line feed.
+func quote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	return `"` + v + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
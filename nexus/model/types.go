@@ -0,0 +1,141 @@
+package model
+
+// --- Structs ---
+
+// LibConfig is a lib_config.json file. Read it with analyzer.ParseConfig,
+// which accepts camelCase and snake_case keys.
+type LibConfig struct {
+	Version          int      `json:"version,omitempty"`
+	HasNestedDomains bool     `json:"hasNestedDomains"`
+	Domains          []string `json:"domains"`
+	IsDomain         bool     `json:"isDomain"`
+
+	// Version 2: settings of this folder, and of sub-domains when "domains"
+	// lists objects instead of names. A sub-domain's own file wins.
+	DomainSettings
+	Nested map[string]DomainSettings `json:"-"`
+}
+
+// DomainSettings are the per-domain options of version 2 configs.
+type DomainSettings struct {
+	DisplayName string `json:"displayName,omitempty"` // Human-readable name for docs and browse
+	RoutePrefix string `json:"routePrefix,omitempty"` // Replaces the namespace in routes, e.g. "payments.v2"
+	Visibility  string `json:"visibility,omitempty"`  // VisibilityPublic (default) or VisibilityPrivate
+}
+
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private" // Not exposed; nested domains inherit it unless they override it
+)
+
+type FunctionMetadata struct {
+	Name           string
+	Params         []Param
+	Returns        []string
+	RequestStruct  string
+	ResponseStruct string
+	Comment        string
+}
+
+type Param struct {
+	Name      string
+	Type      string
+	JSONTag   string
+	FieldName string // PascalCase for struct
+}
+
+type StructMetadata struct {
+	Name      string
+	JsonName  string // Snake case of struct name for potential usage
+	Fields    []StructField
+	Namespace string
+}
+
+type StructField struct {
+	Name    string
+	Type    string
+	JSONTag string // Value of the json tag, e.g. "amount,omitempty"
+	Tag     string `json:",omitempty"` // Full struct tag, e.g. json:"amount" validate:"gt=0"
+}
+
+type EnumMetadata struct {
+	Name      string
+	Type      string // Underlying type: "string", "int", ...
+	Values    []EnumValue
+	Namespace string
+}
+
+type EnumValue struct {
+	Name  string
+	Value string // Literal value, empty when it cannot be resolved statically
+}
+
+// NamedType is a named basic type without constants, e.g. type Cents int64.
+// Named basic types with constants are enums.
+type NamedType struct {
+	Name      string
+	Type      string // Underlying type: "string", "int64", ...
+	Namespace string
+}
+
+type Catalog struct {
+	Services []ServiceEntry   `json:"services"`
+	Structs  []StructMetadata `json:"structs"`
+	Enums    []EnumMetadata   `json:"enums,omitempty"`
+	Types    []NamedType      `json:"types,omitempty"`
+	Domains  []DomainInfo     `json:"domains,omitempty"` // Only domains with settings
+
+	HealthChecks []HealthCheck `json:"health_checks,omitempty"`
+}
+
+// HealthCheck is a domain declaring func HealthCheck() error. The generated
+// server runs it as a readiness check instead of exposing it as a service.
+type HealthCheck struct {
+	Namespace  string `json:"namespace"`
+	ImportPath string `json:"import_path"`
+}
+
+// DomainInfo records the lib_config.json settings of a domain.
+type DomainInfo struct {
+	Namespace   string `json:"namespace"`
+	DisplayName string `json:"display_name,omitempty"`
+	RoutePrefix string `json:"route_prefix,omitempty"`
+}
+
+type ServiceEntry struct {
+	Namespace   string          `json:"namespace"`
+	Method      string          `json:"method"`
+	Route       string          `json:"route,omitempty"` // Set when a routePrefix applies; see Endpoint
+	ImportPath  string          `json:"import_path"`
+	Description string          `json:"description"`
+	Inputs      []ParamMetadata `json:"inputs"`
+	Outputs     []ParamMetadata `json:"outputs"`
+	Flatten     bool            `json:"flatten,omitempty"` // The fields of the only input, a struct, may also be given at the top level of params
+}
+
+// Endpoint is the route the service is served at, without the leading
+// slash: "<namespace>.<Method>" unless a routePrefix replaced the namespace.
+func (s ServiceEntry) Endpoint() string {
+	if s.Route != "" {
+		return s.Route
+	}
+	return s.Namespace + "." + s.Method
+}
+
+type ParamMetadata struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Validate string `json:"validate,omitempty"` // Rules from a //nexus:validate directive (inputs only)
+}
+
+type SearchResult struct {
+	Namespace    string   `json:"namespace"`
+	Method       string   `json:"method"`
+	MatchedParam string   `json:"matched_param"`
+	ParamType    string   `json:"param_type"`            // "Input", "Output", "Struct", "Struct Field", "Signature", ...
+	Description  string   `json:"description"`           // Service description
+	StructName   string   `json:"struct_name,omitempty"` // Name of the struct if matched (optional)
+	FieldName    string   `json:"field_name,omitempty"`  // Name of the field if matched (optional)
+	Score        float64  `json:"score,omitempty"`       // Ranking score (fuzzy search only)
+	Reasons      []string `json:"reasons,omitempty"`     // Why the service matched, with the hit in [brackets]
+}
@@ -7,7 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/japablazatww/nexus/nexus/cmd/nexus-cli/internal/model"
+	"github.com/japablazatww/nexus/nexus/model"
 )
 
 func ResolveDefaultCatalog() string {
@@ -31,15 +31,17 @@ func SearchByParam(catalog model.Catalog, query string) []model.SearchResult {
 		// Check Inputs
 		for _, inp := range svc.Inputs {
 			// Naive check: does the type name contain the struct name?
-			// Type could be "*LoanRequest", "[]LoanRequest", "LoanRequest"
-			// We will just store the service for every param type involved.
-			cleanType := strings.TrimLeft(inp.Type, "[]*")
-			structUsage[cleanType] = append(structUsage[cleanType], svc)
+			// Type could be "*LoanRequest", "[]LoanRequest", "pkg.LoanRequest",
+			// "map[string]LoanRequest": store the service for every named type involved.
+			for _, name := range BaseTypeNames(inp.Type) {
+				structUsage[name] = append(structUsage[name], svc)
+			}
 		}
 		// Check Outputs
 		for _, out := range svc.Outputs {
-			cleanType := strings.TrimLeft(out.Type, "[]*")
-			structUsage[cleanType] = append(structUsage[cleanType], svc)
+			for _, name := range BaseTypeNames(out.Type) {
+				structUsage[name] = append(structUsage[name], svc)
+			}
 		}
 	}
 
@@ -0,0 +1,368 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// Match kinds, from strongest to weakest.
+const (
+	MatchExact     = "exact"
+	MatchPrefix    = "prefix"
+	MatchSubstring = "substring"
+	MatchToken     = "token"
+	MatchFuzzy     = "fuzzy"
+)
+
+var matchScores = map[string]float64{
+	MatchExact:     1.0,
+	MatchPrefix:    0.8,
+	MatchToken:     0.7,
+	MatchSubstring: 0.4,
+	MatchFuzzy:     0.3,
+}
+
+// Field weights: a hit on the method name matters more than one buried in
+// a struct field of a parameter.
+const (
+	weightMethod      = 3.0
+	weightInput       = 2.0
+	weightOutput      = 1.5
+	weightStruct      = 1.5
+	weightNamespace   = 1.2
+	weightStructField = 1.0
+	weightDescription = 0.8
+)
+
+// Index is a full-text index over the catalog. Build it once with NewIndex
+// and reuse it for every query.
+type Index struct {
+	docs []document
+}
+
+type document struct {
+	service model.ServiceEntry
+	fields  []indexedField
+}
+
+type indexedField struct {
+	kind       string // "method", "namespace", "description", "input", "output", "struct", "struct field"
+	text       string // Original text, used for highlighting
+	structName string
+	normalized string
+	tokens     []string
+	weight     float64
+}
+
+// NewIndex indexes method names, namespaces, descriptions, parameter names
+// and the structs (and their fields) used by every service.
+func NewIndex(catalog model.Catalog) *Index {
+	structs := make(map[string]model.StructMetadata) // "namespace.Name" -> struct
+	for _, s := range catalog.Structs {
+		structs[s.Namespace+"."+s.Name] = s
+	}
+
+	ix := &Index{}
+	for _, svc := range catalog.Services {
+		doc := document{service: svc}
+		doc.add("method", svc.Method, "", weightMethod)
+		doc.add("namespace", svc.Namespace, "", weightNamespace)
+		if svc.Description != "" {
+			doc.add("description", svc.Description, "", weightDescription)
+		}
+
+		seen := make(map[string]bool)
+		addParams := func(params []model.ParamMetadata, kind string, weight float64) {
+			for _, p := range params {
+				if p.Type == "error" {
+					continue
+				}
+				if !strings.HasPrefix(p.Name, "result_") {
+					doc.add(kind, p.Name, "", weight)
+				}
+				s, ok := structs[svc.Namespace+"."+strings.TrimLeft(p.Type, "[]*")]
+				if !ok || seen[s.Name] {
+					continue
+				}
+				seen[s.Name] = true
+				doc.add("struct", s.Name, s.Name, weightStruct)
+				for _, f := range s.Fields {
+					doc.add("struct field", f.Name, s.Name, weightStructField)
+				}
+			}
+		}
+		addParams(svc.Inputs, "input", weightInput)
+		addParams(svc.Outputs, "output", weightOutput)
+
+		ix.docs = append(ix.docs, doc)
+	}
+	return ix
+}
+
+func (d *document) add(kind, text, structName string, weight float64) {
+	d.fields = append(d.fields, indexedField{
+		kind:       kind,
+		text:       text,
+		structName: structName,
+		normalized: normalize(text),
+		tokens:     Tokenize(text),
+		weight:     weight,
+	})
+}
+
+type fieldMatch struct {
+	field *indexedField
+	kind  string
+	term  string
+	score float64
+}
+
+// Search ranks every service against the query. Each query token is matched
+// against every indexed field (see score). Services without any hit are
+// dropped; limit <= 0 returns everything.
+func (ix *Index) Search(query string, limit int) []model.SearchResult {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	// "user_id" is also tried as a single term, so it hits the catalog name
+	// of userID, "user_i_d", whose letters are split into tokens
+	whole := normalize(strings.Join(terms, ""))
+
+	var results []model.SearchResult
+	for i := range ix.docs {
+		doc := &ix.docs[i]
+
+		score, matches := doc.score(terms)
+		if len(terms) > 1 {
+			alt, altMatches := doc.score([]string{whole})
+			if len(altMatches) > 0 && (altMatches[0].kind == MatchExact || altMatches[0].kind == MatchPrefix) && alt*float64(len(terms)) > score {
+				score, matches = alt*float64(len(terms)), altMatches
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		sort.SliceStable(matches, func(a, b int) bool { return matches[a].score > matches[b].score })
+		top := matches[0]
+		res := model.SearchResult{
+			Namespace:    doc.service.Namespace,
+			Method:       doc.service.Method,
+			MatchedParam: top.field.text,
+			ParamType:    paramType(top.field.kind),
+			Description:  doc.service.Description,
+			Score:        float64(int(score*100+0.5)) / 100,
+		}
+		if top.field.structName != "" {
+			res.StructName = top.field.structName
+			if top.field.kind == "struct field" {
+				res.FieldName = top.field.text
+				res.MatchedParam = top.field.structName + "." + top.field.text
+			}
+		}
+
+		for _, m := range matches {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("%s %s (%s %q)", m.field.kind, Highlight(m.field.text, m.term), m.kind, m.term))
+		}
+		results = append(results, res)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Namespace+"."+results[i].Method < results[j].Namespace+"."+results[j].Method
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// score returns the sum of the best hit per term, scaled by the share of
+// terms that matched anything, and those hits.
+func (d *document) score(terms []string) (float64, []fieldMatch) {
+	var matches []fieldMatch
+	total := 0.0
+	for _, term := range terms {
+		best := fieldMatch{}
+		for j := range d.fields {
+			f := &d.fields[j]
+			kind := matchField(term, f)
+			if kind == "" {
+				continue
+			}
+			if score := matchScores[kind] * f.weight; score > best.score {
+				best = fieldMatch{field: f, kind: kind, term: term, score: score}
+			}
+		}
+		if best.field != nil {
+			total += best.score
+			matches = append(matches, best)
+		}
+	}
+	return total * float64(len(matches)) / float64(len(terms)), matches
+}
+
+// Fuzzy is a one-shot helper for callers that search only once.
+func Fuzzy(catalog model.Catalog, query string, limit int) []model.SearchResult {
+	return NewIndex(catalog).Search(query, limit)
+}
+
+// matchField returns the strongest match kind of term in f, or "".
+func matchField(term string, f *indexedField) string {
+	switch {
+	case f.normalized == term:
+		return MatchExact
+	case strings.HasPrefix(f.normalized, term):
+		return MatchPrefix
+	}
+	for _, tok := range f.tokens {
+		if tok == term {
+			return MatchToken
+		}
+	}
+	for _, tok := range f.tokens {
+		if strings.HasPrefix(tok, term) {
+			return MatchPrefix
+		}
+	}
+	if len(term) >= 3 && strings.Contains(f.normalized, term) {
+		return MatchSubstring
+	}
+	if max := maxEdits(term); max > 0 {
+		for _, tok := range f.tokens {
+			if abs(len(tok)-len(term)) <= max && editDistance(tok, term) <= max {
+				return MatchFuzzy
+			}
+		}
+		if abs(len(f.normalized)-len(term)) <= max && editDistance(f.normalized, term) <= max {
+			return MatchFuzzy
+		}
+	}
+	return ""
+}
+
+// maxEdits is the typo budget for a term: none for short words, where a
+// single edit already changes the meaning.
+func maxEdits(term string) int {
+	switch {
+	case len(term) >= 8:
+		return 2
+	case len(term) >= 4:
+		return 1
+	}
+	return 0
+}
+
+func paramType(kind string) string {
+	switch kind {
+	case "input":
+		return "Input"
+	case "output":
+		return "Output"
+	case "struct":
+		return "Struct"
+	case "struct field":
+		return "Struct Field"
+	case "method":
+		return "Method"
+	case "namespace":
+		return "Namespace"
+	}
+	return "Description"
+}
+
+// Tokenize splits identifiers and prose into lowercase words:
+// "GetUserBalance" -> get, user, balance; "account_i_d" -> account, i, d
+func Tokenize(s string) []string {
+	var tokens []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Split "userBalance" and the end of an acronym: "HTTPServer" -> http, server
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return tokens
+}
+
+// Highlight wraps the first occurrence of term in text with brackets,
+// ignoring case and underscores: Highlight("GetUserBalance", "balance")
+// -> "GetUser[Balance]". Text is returned unchanged when there is no
+// literal occurrence (fuzzy matches).
+func Highlight(text, term string) string {
+	// Map each normalized rune back to its position in text
+	var norm []rune
+	var pos []int
+	for i, r := range text {
+		if r == '_' {
+			continue
+		}
+		norm = append(norm, unicode.ToLower(r))
+		pos = append(pos, i)
+	}
+
+	idx := strings.Index(string(norm), term)
+	if idx < 0 || term == "" {
+		return text
+	}
+	start := len([]rune(string(norm)[:idx]))
+	end := start + len([]rune(term)) - 1
+
+	from := pos[start]
+	to := pos[end] + len(string([]rune(text[pos[end]:])[0]))
+	return text[:from] + "[" + text[from:to] + "]" + text[to:]
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,41 @@
+package search
+
+import "github.com/japablazatww/nexus/nexus/model"
+
+// Schema identifiers of the documents below, shared by the CLI output and
+// the discovery API. Fields are only ever added to a schema version, never
+// renamed or removed.
+const (
+	SearchSchema   = "nexus.search/v1"
+	ServicesSchema = "nexus.services/v1"
+)
+
+// SearchReport is the document written for search results.
+type SearchReport struct {
+	Schema  string               `json:"schema"`
+	Mode    string               `json:"mode"` // "param", "signature" or "fuzzy"
+	Query   string               `json:"query"`
+	Count   int                  `json:"count"`
+	Results []model.SearchResult `json:"results"`
+}
+
+// ServiceList is the document written for the service listing.
+type ServiceList struct {
+	Schema   string               `json:"schema"`
+	Count    int                  `json:"count"`
+	Services []model.ServiceEntry `json:"services"`
+}
+
+func NewSearchReport(mode string, query string, results []model.SearchResult) SearchReport {
+	if results == nil {
+		results = []model.SearchResult{}
+	}
+	return SearchReport{Schema: SearchSchema, Mode: mode, Query: query, Count: len(results), Results: results}
+}
+
+func NewServiceList(services []model.ServiceEntry) ServiceList {
+	if services == nil {
+		services = []model.ServiceEntry{}
+	}
+	return ServiceList{Schema: ServicesSchema, Count: len(services), Services: services}
+}
@@ -0,0 +1,178 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/japablazatww/nexus/nexus/model"
+)
+
+// SearchByType finds services by signature. inputTypes and outputTypes are
+// comma-separated type queries; every query must match a distinct input
+// (resp. output) of the service. Either side may be empty.
+//
+// A bare name matches the type in any form, so "TransferRequest" matches
+// "national.TransferRequest", "*TransferRequest" and "[]TransferRequest".
+// A query that spells out markers ("[]string", "*LoanRequest") only matches
+// that exact shape, still ignoring package qualifiers.
+func SearchByType(catalog model.Catalog, inputTypes string, outputTypes string) []model.SearchResult {
+	inQueries := splitTypeQueries(inputTypes)
+	outQueries := splitTypeQueries(outputTypes)
+	if len(inQueries) == 0 && len(outQueries) == 0 {
+		return nil
+	}
+
+	var results []model.SearchResult
+	for _, svc := range catalog.Services {
+		inHits, ok := matchParams(svc.Inputs, inQueries)
+		if !ok {
+			continue
+		}
+		outHits, ok := matchParams(svc.Outputs, outQueries)
+		if !ok {
+			continue
+		}
+
+		res := model.SearchResult{
+			Namespace:    svc.Namespace,
+			Method:       svc.Method,
+			MatchedParam: Signature(svc),
+			ParamType:    "Signature",
+			Description:  svc.Description,
+		}
+		for _, p := range inHits {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("input %s (%s)", p.Name, p.Type))
+		}
+		for _, p := range outHits {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("output %s (%s)", p.Name, p.Type))
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// Signature renders a service as Go-like text: Transfer(from_account string, amount float64) (string, error)
+func Signature(svc model.ServiceEntry) string {
+	var in []string
+	for _, p := range svc.Inputs {
+		in = append(in, p.Name+" "+p.Type)
+	}
+	var out []string
+	for _, p := range svc.Outputs {
+		out = append(out, p.Type)
+	}
+
+	sig := svc.Method + "(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+		return sig
+	case 1:
+		return sig + " " + out[0]
+	}
+	return sig + " (" + strings.Join(out, ", ") + ")"
+}
+
+func splitTypeQueries(s string) []string {
+	var queries []string
+	for _, q := range strings.Split(s, ",") {
+		if q = strings.TrimSpace(q); q != "" {
+			queries = append(queries, q)
+		}
+	}
+	return queries
+}
+
+// matchParams assigns every query to a different param, first come first
+// served. It reports false when some query is left without a param.
+func matchParams(params []model.ParamMetadata, queries []string) ([]model.ParamMetadata, bool) {
+	used := make([]bool, len(params))
+	var hits []model.ParamMetadata
+	for _, q := range queries {
+		found := false
+		for i, p := range params {
+			if !used[i] && TypeMatches(p.Type, q) {
+				used[i] = true
+				hits = append(hits, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return hits, true
+}
+
+// TypeMatches reports whether a catalog type satisfies a type query.
+func TypeMatches(goType string, query string) bool {
+	if strings.ContainsAny(query, "[]*") {
+		return normalize(unqualify(goType)) == normalize(unqualify(query))
+	}
+	for _, name := range BaseTypeNames(goType) {
+		if normalize(name) == normalize(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// unqualify drops package qualifiers from a type expression:
+// "[]*national.TransferRequest" -> "[]*TransferRequest"
+func unqualify(goType string) string {
+	var sb strings.Builder
+	start := 0
+	for i, r := range goType {
+		switch r {
+		case '[', ']', '*':
+			sb.WriteString(stripQualifier(goType[start:i]))
+			sb.WriteRune(r)
+			start = i + 1
+		}
+	}
+	sb.WriteString(stripQualifier(goType[start:]))
+	return sb.String()
+}
+
+func stripQualifier(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// BaseTypeNames returns the named types inside a type expression, without
+// pointer/slice/array markers or package qualifiers:
+// "[]*national.TransferRequest" -> [TransferRequest], "map[string]Loan" -> [string Loan]
+func BaseTypeNames(goType string) []string {
+	if rest, ok := strings.CutPrefix(goType, "map["); ok {
+		depth := 1
+		for i, r := range rest {
+			switch r {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+			if depth == 0 {
+				return append(BaseTypeNames(rest[:i]), BaseTypeNames(rest[i+1:])...)
+			}
+		}
+		return nil
+	}
+	if rest, ok := strings.CutPrefix(goType, "*"); ok {
+		return BaseTypeNames(rest)
+	}
+	if strings.HasPrefix(goType, "[") {
+		if i := strings.Index(goType, "]"); i >= 0 {
+			return BaseTypeNames(goType[i+1:])
+		}
+	}
+	if i := strings.LastIndex(goType, "."); i >= 0 {
+		goType = goType[i+1:]
+	}
+	if goType == "" {
+		return nil
+	}
+	return []string{goType}
+}